@@ -1,5 +1,9 @@
 package hlp
 
+import (
+	"fmt"
+)
+
 func must(err error) {
 	if err == nil {
 		return
@@ -13,3 +17,19 @@ func Must[T any](out T, err error) T {
 	must(err)
 	return out
 }
+
+// ElementError annotates an error returned by a callback with the element of the collection it was processing. It is
+// returned (joined with any others) from the *ErrAll family of functions, and can be retrieved with errors.As
+type ElementError[T any] struct {
+	Index int
+	Item  T
+	Err   error
+}
+
+func (e *ElementError[T]) Error() string {
+	return fmt.Sprintf("element %v (%v): %v", e.Index, e.Item, e.Err)
+}
+
+func (e *ElementError[T]) Unwrap() error {
+	return e.Err
+}
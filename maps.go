@@ -1,5 +1,9 @@
 package hlp
 
+import (
+	"errors"
+)
+
 // MapFromSliceErr is like MapFromSlice, but the callback function can fail. In such a case, a nil slice and the error from
 // the callback is returned. This function fails fast; i.e it stops iteration at the first non-nil error
 func MapFromSliceErr[T any, K comparable, V any](collection []T, callback func(item T, index int) (K, V, error)) (map[K]V, error) {
@@ -10,6 +14,15 @@ func MapFromSliceErr[T any, K comparable, V any](collection []T, callback func(i
 	return out, err
 }
 
+// MapFromSliceErrAll is like MapFromSliceErr, but does not stop at the first error. See FilteredMapFromSliceErrAll for
+// details
+func MapFromSliceErrAll[T any, K comparable, V any](collection []T, callback func(item T, index int) (K, V, error)) (map[K]V, error) {
+	return FilteredMapFromSliceErrAll(collection, func(item T, index int) (K, V, bool, error) {
+		key, val, err := callback(item, index)
+		return key, val, true, err
+	})
+}
+
 // MapFromSlice returns a map, whose keys & values are the return values from applying the callback function to each
 // element of the given slice
 func MapFromSlice[T any, K comparable, V any](collection []T, callback func(item T, index int) (K, V)) map[K]V {
@@ -40,6 +53,28 @@ func FilteredMapFromSliceErr[T any, K comparable, V any](collection []T, callbac
 	return out, nil
 }
 
+// FilteredMapFromSliceErrAll is like FilteredMapFromSliceErr, but does not stop at the first error. The callback is run
+// for every element, and the map of successful results is returned along with every failure, each wrapped in an
+// ElementError and joined with errors.Join
+func FilteredMapFromSliceErrAll[T any, K comparable, V any](collection []T, callback func(item T, index int) (K, V, bool, error)) (map[K]V, error) {
+	out := map[K]V{}
+	errs := []error{}
+
+	for i, item := range collection {
+		key, val, ok, err := callback(item, i)
+		if err != nil {
+			errs = append(errs, &ElementError[T]{Index: i, Item: item, Err: err})
+			continue
+		}
+		if !ok {
+			continue
+		}
+		out[key] = val
+	}
+
+	return out, errors.Join(errs...)
+}
+
 // FilteredMapFromSlice is the same as FilteredMapFromSliceErr, except the callback cannot return error
 func FilteredMapFromSlice[T any, K comparable, V any](collection []T, callback func(item T, index int) (K, V, bool)) map[K]V {
 	out, _ := FilteredMapFromSliceErr(collection, func(item T, index int) (K, V, bool, error) {
@@ -167,6 +167,50 @@ func TestFilteredMapFromSliceErr(t *testing.T) {
 	})
 }
 
+func TestFilteredMapFromSliceErrAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("error case", func(t *testing.T) {
+		t.Parallel()
+
+		input := []string{"one", "three", "four", "six", "seven"}
+		got, err := FilteredMapFromSliceErrAll(input, func(item string, index int) (int, string, bool, error) {
+			if item == "four" {
+				return 0, "", false, errInternalTestingError
+			}
+			return index, item, item != "seven", nil
+		})
+		require.ErrorIs(t, err, errInternalTestingError)
+		var elemErr *ElementError[string]
+		require.ErrorAs(t, err, &elemErr)
+		require.Equal(t, 2, elemErr.Index)
+		require.Equal(t, "four", elemErr.Item)
+		require.Equal(
+			t,
+			map[int]string{
+				0: "one",
+				1: "three",
+				3: "six",
+			},
+			got,
+		)
+	})
+}
+
+func TestMapFromSliceErrAll(t *testing.T) {
+	t.Parallel()
+
+	input := []string{"one", "three", "four"}
+	got, err := MapFromSliceErrAll(input, func(item string, index int) (string, int, error) {
+		if index == 0 {
+			return "", 0, errInternalTestingError
+		}
+		return item, index, nil
+	})
+	require.ErrorIs(t, err, errInternalTestingError)
+	require.Equal(t, map[string]int{"three": 1, "four": 2}, got)
+}
+
 func TestFilteredMapFromSlice(t *testing.T) {
 	t.Parallel()
 
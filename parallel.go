@@ -0,0 +1,122 @@
+package hlp
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+var errCallbackPanicked = errors.New("callback panicked")
+
+// ParallelOpts are options used to configure the behavior of the Parallel* family of functions
+type ParallelOpts struct {
+	// Concurrency is the maximum number of callbacks run at once. Defaults to runtime.GOMAXPROCS(0)
+	Concurrency int
+	// CollectErrors runs the callback for every element regardless of failures, returning all errors joined with
+	// errors.Join in input order. By default the functions fail fast, cancelling the context passed to outstanding
+	// callbacks and starting no further work after the first error
+	CollectErrors bool
+}
+
+// ParallelFilterMapErr is like FilterMapErr, but runs the callback concurrently, up to the limit specified in opts. The
+// output preserves the order of the input. The context passed to the callback is cancelled when the parent context is
+// cancelled, or on the first error unless opts.CollectErrors is set. On failure, a nil slice and the error is returned.
+// If a callback panics, outstanding callbacks are cancelled and the first panic value is re-raised on the calling
+// goroutine once all started callbacks have returned, so it can be recovered as with FilterMapErr
+func ParallelFilterMapErr[T any, R any](ctx context.Context, collection []T, opts ParallelOpts, callback func(ctx context.Context, item T, index int) (R, bool, error)) ([]R, error) {
+	limit := opts.Concurrency
+	if limit <= 0 {
+		limit = runtime.GOMAXPROCS(0)
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	results := make([]R, len(collection))
+	keep := make([]bool, len(collection))
+	errs := make([]error, len(collection))
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	var panicOnce sync.Once
+	var panicked bool
+	var panicValue any
+
+	for i, item := range collection {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		// Both cases of the select may be ready at once, so cancellation has to be checked explicitly
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			defer func() {
+				if r := recover(); r != nil {
+					panicOnce.Do(func() {
+						panicked = true
+						panicValue = r
+					})
+					cancel(errCallbackPanicked)
+				}
+			}()
+
+			out, ok, err := callback(ctx, item, i)
+			if err != nil {
+				errs[i] = err
+				if !opts.CollectErrors {
+					cancel(err)
+				}
+				return
+			}
+			results[i] = out
+			keep[i] = ok
+		}()
+	}
+	wg.Wait()
+
+	if panicked {
+		panic(panicValue)
+	}
+
+	if opts.CollectErrors {
+		if ctx.Err() != nil {
+			errs = append(errs, context.Cause(ctx))
+		}
+		if err := errors.Join(errs...); err != nil {
+			return nil, err
+		}
+	} else if ctx.Err() != nil {
+		// The cause is either the first callback error or the reason the parent context was cancelled
+		return nil, context.Cause(ctx)
+	}
+
+	out := make([]R, 0, len(collection))
+	for i := range results {
+		if keep[i] {
+			out = append(out, results[i])
+		}
+	}
+	return out, nil
+}
+
+// ParallelMapErr is like MapErr, but runs the callback concurrently. See ParallelFilterMapErr for details
+func ParallelMapErr[T any, R any](ctx context.Context, collection []T, opts ParallelOpts, callback func(ctx context.Context, item T, index int) (R, error)) ([]R, error) {
+	return ParallelFilterMapErr(ctx, collection, opts, func(ctx context.Context, item T, index int) (R, bool, error) {
+		out, err := callback(ctx, item, index)
+		return out, true, err
+	})
+}
+
+// ParallelFilterErr is like FilterErr, but runs the callback concurrently. See ParallelFilterMapErr for details
+func ParallelFilterErr[T any](ctx context.Context, collection []T, opts ParallelOpts, callback func(ctx context.Context, item T, index int) (bool, error)) ([]T, error) {
+	return ParallelFilterMapErr(ctx, collection, opts, func(ctx context.Context, item T, index int) (T, bool, error) {
+		ok, err := callback(ctx, item, index)
+		return item, ok, err
+	})
+}
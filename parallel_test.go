@@ -0,0 +1,147 @@
+package hlp
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelFilterMapErr(t *testing.T) {
+	t.Run("preserves order", func(t *testing.T) {
+		got, err := ParallelFilterMapErr(
+			context.Background(),
+			intSlice(),
+			ParallelOpts{Concurrency: 3},
+			func(_ context.Context, item int, index int) (int, bool, error) {
+				// Later elements finish first
+				time.Sleep(time.Duration(6-index) * time.Millisecond)
+				return item * 10, item%2 == 0, nil
+			},
+		)
+		require.NoError(t, err)
+		require.Equal(t, []int{20, 40, 60}, got)
+	})
+
+	t.Run("respects concurrency limit", func(t *testing.T) {
+		var running, peak atomic.Int32
+		_, err := ParallelFilterMapErr(
+			context.Background(),
+			FillFunc(20, func(i int) int { return i }),
+			ParallelOpts{Concurrency: 2},
+			func(_ context.Context, item int, _ int) (int, bool, error) {
+				now := running.Add(1)
+				defer running.Add(-1)
+				for {
+					old := peak.Load()
+					if now <= old || peak.CompareAndSwap(old, now) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				return item, true, nil
+			},
+		)
+		require.NoError(t, err)
+		require.LessOrEqual(t, peak.Load(), int32(2))
+	})
+
+	t.Run("fails fast", func(t *testing.T) {
+		var started atomic.Int32
+		got, err := ParallelFilterMapErr(
+			context.Background(),
+			FillFunc(100, func(i int) int { return i }),
+			ParallelOpts{Concurrency: 2},
+			func(ctx context.Context, item int, _ int) (int, bool, error) {
+				started.Add(1)
+				if item == 1 {
+					return 0, false, errInternalTestingError
+				}
+				<-ctx.Done()
+				return 0, false, ctx.Err()
+			},
+		)
+		require.ErrorIs(t, err, errInternalTestingError)
+		require.NotErrorIs(t, err, context.Canceled)
+		require.Nil(t, got)
+		require.Less(t, started.Load(), int32(100))
+	})
+
+	t.Run("collects errors", func(t *testing.T) {
+		errOther := errors.New("other")
+		var calls atomic.Int32
+		got, err := ParallelFilterMapErr(
+			context.Background(),
+			intSlice(),
+			ParallelOpts{Concurrency: 2, CollectErrors: true},
+			func(_ context.Context, item int, _ int) (int, bool, error) {
+				calls.Add(1)
+				switch item {
+				case 2:
+					return 0, false, errInternalTestingError
+				case 5:
+					return 0, false, errOther
+				}
+				return item, true, nil
+			},
+		)
+		require.ErrorIs(t, err, errInternalTestingError)
+		require.ErrorIs(t, err, errOther)
+		require.Equal(t, "internal testing error\nother", err.Error())
+		require.Nil(t, got)
+		require.Equal(t, int32(6), calls.Load())
+	})
+
+	t.Run("parent cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := ParallelFilterMapErr(ctx, intSlice(), ParallelOpts{}, func(_ context.Context, item int, _ int) (int, bool, error) {
+			return item, true, nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("re-panics on the calling goroutine", func(t *testing.T) {
+		var started atomic.Int32
+		require.PanicsWithValue(t, "boom", func() {
+			_, _ = ParallelFilterMapErr(
+				context.Background(),
+				FillFunc(100, func(i int) int { return i }),
+				ParallelOpts{Concurrency: 2},
+				func(ctx context.Context, item int, _ int) (int, bool, error) {
+					started.Add(1)
+					if item == 1 {
+						panic("boom")
+					}
+					<-ctx.Done()
+					return 0, false, ctx.Err()
+				},
+			)
+		})
+		require.Less(t, started.Load(), int32(100))
+	})
+}
+
+func TestParallelMapErr(t *testing.T) {
+	got, err := ParallelMapErr(context.Background(), intSlice(), ParallelOpts{}, func(_ context.Context, item int, index int) (int, error) {
+		return item + index, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 3, 5, 7, 9, 11}, got)
+}
+
+func TestParallelFilterErr(t *testing.T) {
+	got, err := ParallelFilterErr(context.Background(), intSlice(), ParallelOpts{}, func(_ context.Context, item int, _ int) (bool, error) {
+		return item > 3, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{4, 5, 6}, got)
+
+	_, err = ParallelFilterErr(context.Background(), intSlice(), ParallelOpts{}, func(_ context.Context, item int, _ int) (bool, error) {
+		return false, errInternalTestingError
+	})
+	require.ErrorIs(t, err, errInternalTestingError)
+}
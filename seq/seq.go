@@ -0,0 +1,181 @@
+package seq
+
+import (
+	"iter"
+)
+
+// FilterMap lazily transforms the elements of the input sequence with the callback function, yielding only the
+// results for which the callback also returns true. The index passed to the callback is the position in the input
+func FilterMap[T any, R any](seq iter.Seq[T], callback func(item T, index int) (R, bool)) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		i := 0
+		for item := range seq {
+			out, ok := callback(item, i)
+			i += 1
+			if !ok {
+				continue
+			}
+			if !yield(out) {
+				return
+			}
+		}
+	}
+}
+
+// Map lazily transforms all elements of the input sequence with the supplied callback function
+func Map[T any, R any](seq iter.Seq[T], callback func(item T, index int) R) iter.Seq[R] {
+	return FilterMap(seq, func(item T, index int) (R, bool) {
+		return callback(item, index), true
+	})
+}
+
+// Filter lazily yields the elements of the input sequence the supplied callback returns true for
+func Filter[T any](seq iter.Seq[T], callback func(item T, index int) bool) iter.Seq[T] {
+	return FilterMap(seq, func(item T, index int) (T, bool) {
+		return item, callback(item, index)
+	})
+}
+
+// Take yields at most the first n elements of the input sequence, stopping the input once they have been yielded
+func Take[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		count := 0
+		for item := range seq {
+			if !yield(item) {
+				return
+			}
+			count += 1
+			if count >= n {
+				return
+			}
+		}
+	}
+}
+
+// Skip yields all but the first n elements of the input sequence
+func Skip[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		count := 0
+		for item := range seq {
+			if count < n {
+				count += 1
+				continue
+			}
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk yields the elements of the input sequence in slices of the specified size, the last of which may be shorter.
+// Each yielded slice is newly allocated, and so is safe to retain
+func Chunk[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	if size <= 0 {
+		panic("chunk size must be greater than 0")
+	}
+
+	return func(yield func([]T) bool) {
+		chunk := make([]T, 0, size)
+		for item := range seq {
+			chunk = append(chunk, item)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]T, 0, size)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Flatten yields the elements of each of the given sequences in turn
+func Flatten[T any](seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, seq := range seqs {
+			for item := range seq {
+				if !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Zip yields pairs of elements from both sequences, stopping when either is exhausted
+func Zip[A any, B any](a iter.Seq[A], b iter.Seq[B]) iter.Seq2[A, B] {
+	return func(yield func(A, B) bool) {
+		nextB, stop := iter.Pull(b)
+		defer stop()
+
+		for itemA := range a {
+			itemB, ok := nextB()
+			if !ok {
+				return
+			}
+			if !yield(itemA, itemB) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate yields each element of the input sequence along with its index
+func Enumerate[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for item := range seq {
+			if !yield(i, item) {
+				return
+			}
+			i += 1
+		}
+	}
+}
+
+// Keys yields the first element of each pair in the input sequence
+func Keys[K any, V any](seq iter.Seq2[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range seq {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// Values yields the second element of each pair in the input sequence. This can be used to drop the index from
+// set.Set.Iter and hashset.Set.Iter
+func Values[K any, V any](seq iter.Seq2[K, V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range seq {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Collect gathers all elements of the sequence into a slice
+func Collect[T any](seq iter.Seq[T]) []T {
+	out := []T{}
+	for item := range seq {
+		out = append(out, item)
+	}
+	return out
+}
+
+// CollectMap gathers all pairs of the sequence into a map, with later pairs replacing earlier pairs with the same key
+func CollectMap[K comparable, V any](seq iter.Seq2[K, V]) map[K]V {
+	out := map[K]V{}
+	for k, v := range seq {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package seq
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/nicjohnson145/hlp/set"
+)
+
+func ExampleMap() {
+	doubled := Map(slices.Values([]int{1, 2, 3}), func(item int, _ int) int {
+		return item * 2
+	})
+	fmt.Println(Collect(doubled))
+	// Output: [2 4 6]
+}
+
+func ExampleTake() {
+	evens := Filter(slices.Values([]int{1, 2, 3, 4, 5, 6, 7, 8}), func(item int, _ int) bool {
+		return item%2 == 0
+	})
+	fmt.Println(Collect(Take(evens, 2)))
+	// Output: [2 4]
+}
+
+func ExampleChunk() {
+	for chunk := range Chunk(slices.Values([]string{"a", "b", "c", "d", "e"}), 2) {
+		fmt.Println(chunk)
+	}
+	// Output:
+	// [a b]
+	// [c d]
+	// [e]
+}
+
+func ExampleValues() {
+	s := set.New("apple", "banana", "cherry")
+	long := Collect(Filter(Values(s.Iter()), func(item string, _ int) bool {
+		return len(item) > 5
+	}))
+	sort.Strings(long)
+	fmt.Println(long)
+	// Output: [banana cherry]
+}
@@ -0,0 +1,95 @@
+package seq
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func intSeq() iter.Seq[int] {
+	return slices.Values([]int{1, 2, 3, 4, 5, 6})
+}
+
+// countingSeq yields 1..n, recording how many elements were pulled
+func countingSeq(n int, pulled *int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := 1; i <= n; i++ {
+			*pulled += 1
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestFilterMap(t *testing.T) {
+	got := FilterMap(intSeq(), func(item int, index int) (int, bool) {
+		return item * index, item%2 == 0
+	})
+	require.Equal(t, []int{2, 12, 30}, Collect(got))
+}
+
+func TestMap(t *testing.T) {
+	require.Equal(t, []int{2, 4, 6, 8, 10, 12}, Collect(Map(intSeq(), func(item int, _ int) int { return item * 2 })))
+}
+
+func TestFilter(t *testing.T) {
+	require.Equal(t, []int{2, 4, 6}, Collect(Filter(intSeq(), func(item int, _ int) bool { return item%2 == 0 })))
+}
+
+func TestTake(t *testing.T) {
+	t.Run("short circuits", func(t *testing.T) {
+		pulled := 0
+		require.Equal(t, []int{1, 2, 3}, Collect(Take(countingSeq(1000, &pulled), 3)))
+		require.Equal(t, 3, pulled)
+	})
+
+	t.Run("more than available", func(t *testing.T) {
+		require.Equal(t, []int{1, 2, 3, 4, 5, 6}, Collect(Take(intSeq(), 10)))
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		require.Empty(t, Collect(Take(intSeq(), 0)))
+	})
+}
+
+func TestSkip(t *testing.T) {
+	require.Equal(t, []int{5, 6}, Collect(Skip(intSeq(), 4)))
+	require.Empty(t, Collect(Skip(intSeq(), 10)))
+}
+
+func TestChunk(t *testing.T) {
+	require.Equal(t, [][]int{{1, 2, 3, 4}, {5, 6}}, Collect(Chunk(intSeq(), 4)))
+	require.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}}, Collect(Chunk(intSeq(), 3)))
+	require.Panics(t, func() { Chunk(intSeq(), 0) })
+}
+
+func TestFlatten(t *testing.T) {
+	require.Equal(t, []int{1, 2, 3, 4, 5, 6, 1, 2}, Collect(Flatten(intSeq(), Take(intSeq(), 2))))
+
+	pulled := 0
+	Collect(Take(Flatten(countingSeq(5, &pulled), intSeq()), 2))
+	require.Equal(t, 2, pulled)
+}
+
+func TestZip(t *testing.T) {
+	got := CollectMap(Zip(slices.Values([]string{"a", "b", "c"}), intSeq()))
+	require.Equal(t, map[string]int{"a": 1, "b": 2, "c": 3}, got)
+}
+
+func TestEnumerate(t *testing.T) {
+	got := CollectMap(Enumerate(slices.Values([]string{"a", "b"})))
+	require.Equal(t, map[int]string{0: "a", 1: "b"}, got)
+}
+
+func TestKeysValues(t *testing.T) {
+	pairs := Enumerate(slices.Values([]string{"a", "b"}))
+	require.Equal(t, []int{0, 1}, Collect(Keys(pairs)))
+	require.Equal(t, []string{"a", "b"}, Collect(Values(pairs)))
+}
+
+func TestCollect(t *testing.T) {
+	require.Equal(t, []int{}, Collect(Take(intSeq(), 0)))
+}
@@ -2,6 +2,7 @@ package hlp
 
 import (
 	"cmp"
+	"errors"
 )
 
 // FilterMapErr is like FilterMap, but the callback function can fail. In such a case, a nil slice and the error from
@@ -23,6 +24,28 @@ func FilterMapErr[T any, R any](collection []T, callback func(item T, index int)
 	return result, nil
 }
 
+// FilterMapErrAll is like FilterMapErr, but does not stop at the first error. The callback is run for every element,
+// and the results from successful calls are returned along with every failure, each wrapped in an ElementError and
+// joined with errors.Join
+func FilterMapErrAll[T any, R any](collection []T, callback func(item T, index int) (R, bool, error)) ([]R, error) {
+	result := []R{}
+	errs := []error{}
+
+	for i, item := range collection {
+		r, ok, err := callback(item, i)
+		if err != nil {
+			errs = append(errs, &ElementError[T]{Index: i, Item: item, Err: err})
+			continue
+		}
+		if !ok {
+			continue
+		}
+		result = append(result, r)
+	}
+
+	return result, errors.Join(errs...)
+}
+
 // FilterMap is the combination of Filter & Map, returning the elements from the input slice as transformed by the
 // callback function, but only in cases where the callback function also returns true
 func FilterMap[T any, R any](collection []T, callback func(item T, index int) (R, bool)) []R {
@@ -42,6 +65,14 @@ func MapErr[T any, R any](collection []T, callback func(item T, index int) (R, e
 	})
 }
 
+// MapErrAll is like MapErr, but does not stop at the first error. See FilterMapErrAll for details
+func MapErrAll[T any, R any](collection []T, callback func(item T, index int) (R, error)) ([]R, error) {
+	return FilterMapErrAll[T, R](collection, func(item T, index int) (R, bool, error) {
+		out, err := callback(item, index)
+		return out, true, err
+	})
+}
+
 // Map returns all elements of the input slice as transformed by the supplied callback function
 func Map[T any, R any](collection []T, callback func(item T, index int) R) []R {
 	out, _ := FilterMapErr[T, R](collection, func(item T, index int) (R, bool, error) {
@@ -59,6 +90,14 @@ func FilterErr[T any](collection []T, callback func(item T, index int) (bool, er
 	})
 }
 
+// FilterErrAll is like FilterErr, but does not stop at the first error. See FilterMapErrAll for details
+func FilterErrAll[T any](collection []T, callback func(item T, index int) (bool, error)) ([]T, error) {
+	return FilterMapErrAll[T, T](collection, func(item T, index int) (T, bool, error) {
+		ok, err := callback(item, index)
+		return item, ok, err
+	})
+}
+
 // Filter returns all elements of the input slice the supplied callback returns true for
 func Filter[T any](collection []T, callback func(item T, index int) bool) []T {
 	out, _ := FilterMapErr[T, T](collection, func(item T, index int) (T, bool, error) {
@@ -210,3 +249,74 @@ func compareBy[T any](list []T, compare func(a T, b T) bool) T {
 
 	return out
 }
+
+// UniqBy returns the elements of the slice with duplicates removed, where two elements are duplicates if the key
+// function returns the same value for both. The first occurrence of each key is kept, and the original order preserved
+func UniqBy[T any, K comparable](list []T, key func(item T) K) []T {
+	seen := map[K]struct{}{}
+	out := []T{}
+
+	for _, item := range list {
+		k := key(item)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		out = append(out, item)
+	}
+
+	return out
+}
+
+// Uniq returns the elements of the slice with duplicates removed, keeping the first occurrence of each
+func Uniq[T comparable](list []T) []T {
+	return UniqBy(list, func(item T) T { return item })
+}
+
+// IntersectBy returns the unique elements of the first slice whose key also appears in the second slice, in the order
+// of the first slice
+func IntersectBy[T any, K comparable](a []T, b []T, key func(item T) K) []T {
+	keys := keySet(b, key)
+	return UniqBy(Filter(a, func(item T, _ int) bool {
+		_, ok := keys[key(item)]
+		return ok
+	}), key)
+}
+
+// DifferenceBy returns the unique elements of the first slice whose key does not appear in the second slice, in the
+// order of the first slice
+func DifferenceBy[T any, K comparable](a []T, b []T, key func(item T) K) []T {
+	keys := keySet(b, key)
+	return UniqBy(Filter(a, func(item T, _ int) bool {
+		_, ok := keys[key(item)]
+		return !ok
+	}), key)
+}
+
+// UnionBy returns the unique elements of both slices, in the order they first appear across the first and then the
+// second slice
+func UnionBy[T any, K comparable](a []T, b []T, key func(item T) K) []T {
+	return UniqBy(Flatten(a, b), key)
+}
+
+// CountBy returns the number of elements of the slice for each value returned from the key function
+func CountBy[T any, K comparable](list []T, key func(item T) K) map[K]int {
+	out := map[K]int{}
+	for _, item := range list {
+		out[key(item)] += 1
+	}
+	return out
+}
+
+// Frequencies returns the number of times each element appears in the slice
+func Frequencies[T comparable](list []T) map[T]int {
+	return CountBy(list, func(item T) T { return item })
+}
+
+func keySet[T any, K comparable](list []T, key func(item T) K) map[K]struct{} {
+	out := make(map[K]struct{}, len(list))
+	for _, item := range list {
+		out[key(item)] = struct{}{}
+	}
+	return out
+}
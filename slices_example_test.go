@@ -2,6 +2,7 @@ package hlp
 
 import (
 	"fmt"
+	"strings"
 )
 
 func ExampleExtractRange() {
@@ -50,3 +51,13 @@ func ExampleMinBy() {
 	fmt.Println(MinBy([]int{1, 3, 2, 0}, func(item int, low int) bool { return item < low }))
 	// Output: 0
 }
+
+func ExampleUniq() {
+	fmt.Println(Uniq([]string{"b", "a", "b", "c", "a"}))
+	// Output: [b a c]
+}
+
+func ExampleDifferenceBy() {
+	fmt.Println(DifferenceBy([]string{"Apple", "banana", "Cherry"}, []string{"BANANA"}, strings.ToLower))
+	// Output: [Apple Cherry]
+}
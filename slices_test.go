@@ -2,6 +2,7 @@ package hlp
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -144,6 +145,73 @@ func TestFilterMapErr(t *testing.T) {
 	})
 }
 
+func TestFilterMapErrAll(t *testing.T) {
+	t.Run("error case", func(t *testing.T) {
+		got, err := FilterMapErrAll(
+			intSlice(),
+			func(item, index int) (int, bool, error) {
+				if item == 3 || item == 4 {
+					return 0, false, errInternalTestingError
+				}
+				return item * 2, item%2 == 0, nil
+			},
+		)
+
+		require.Equal(t, []int{4, 12}, got)
+		require.ErrorIs(t, err, errInternalTestingError)
+		require.Equal(t, "element 2 (3): internal testing error\nelement 3 (4): internal testing error", err.Error())
+
+		var elemErr *ElementError[int]
+		require.ErrorAs(t, err, &elemErr)
+		require.Equal(t, 2, elemErr.Index)
+		require.Equal(t, 3, elemErr.Item)
+	})
+
+	t.Run("non error case", func(t *testing.T) {
+		got, err := FilterMapErrAll(
+			intSlice(),
+			func(item, index int) (int, bool, error) {
+				return item * 2, item%2 == 0, nil
+			},
+		)
+
+		require.Equal(t, []int{4, 8, 12}, got)
+		require.NoError(t, err)
+	})
+}
+
+func TestMapErrAll(t *testing.T) {
+	got, err := MapErrAll(
+		intSlice(),
+		func(item, index int) (int, error) {
+			if item == 1 {
+				return 0, errInternalTestingError
+			}
+			return item * 2, nil
+		},
+	)
+
+	require.Equal(t, []int{4, 6, 8, 10, 12}, got)
+	require.ErrorIs(t, err, errInternalTestingError)
+}
+
+func TestFilterErrAll(t *testing.T) {
+	got, err := FilterErrAll(
+		intSlice(),
+		func(item, index int) (bool, error) {
+			if item == 6 {
+				return false, errInternalTestingError
+			}
+			return item%2 == 0, nil
+		},
+	)
+
+	require.Equal(t, []int{2, 4}, got)
+	var elemErr *ElementError[int]
+	require.ErrorAs(t, err, &elemErr)
+	require.Equal(t, 5, elemErr.Index)
+}
+
 func TestFlatten(t *testing.T) {
 	t.Run("smokes", func(t *testing.T) {
 		result := Flatten(
@@ -272,3 +340,37 @@ func TestMinBy(t *testing.T) {
 		require.Equal(t, 0, MinBy([]int{1, 3, 2, 0}, func(item int, low int) bool { return item < low }))
 	})
 }
+
+func TestUniq(t *testing.T) {
+	require.Equal(t, []int{3, 1, 2}, Uniq([]int{3, 1, 3, 2, 1}))
+	require.Equal(t, []int{}, Uniq([]int{}))
+}
+
+func TestUniqBy(t *testing.T) {
+	got := UniqBy([]string{"one", "two", "three", "four", "five"}, func(item string) int { return len(item) })
+	require.Equal(t, []string{"one", "three", "four"}, got)
+}
+
+func TestIntersectBy(t *testing.T) {
+	got := IntersectBy([]string{"a", "B", "c", "A", "d"}, []string{"b", "a", "x"}, strings.ToLower)
+	require.Equal(t, []string{"a", "B"}, got)
+}
+
+func TestDifferenceBy(t *testing.T) {
+	got := DifferenceBy([]string{"a", "B", "c", "C", "d"}, []string{"b", "a", "x"}, strings.ToLower)
+	require.Equal(t, []string{"c", "d"}, got)
+}
+
+func TestUnionBy(t *testing.T) {
+	got := UnionBy([]string{"a", "B", "a"}, []string{"b", "c", "A", "d"}, strings.ToLower)
+	require.Equal(t, []string{"a", "B", "c", "d"}, got)
+}
+
+func TestCountBy(t *testing.T) {
+	got := CountBy([]string{"one", "two", "three", "four", "five"}, func(item string) int { return len(item) })
+	require.Equal(t, map[int]int{3: 2, 5: 1, 4: 2}, got)
+}
+
+func TestFrequencies(t *testing.T) {
+	require.Equal(t, map[string]int{"a": 2, "b": 1}, Frequencies([]string{"a", "b", "a"}))
+}
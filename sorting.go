@@ -0,0 +1,114 @@
+package hlp
+
+import (
+	"cmp"
+	"container/heap"
+	"slices"
+)
+
+// SortKey is a single key of a multi-key sort, created with Ascending or Descending
+type SortKey[T any] func(a T, b T) int
+
+// Ascending sorts by the value returned from the key function, from lowest to highest
+func Ascending[T any, K cmp.Ordered](key func(item T) K) SortKey[T] {
+	return func(a T, b T) int {
+		return cmp.Compare(key(a), key(b))
+	}
+}
+
+// Descending sorts by the value returned from the key function, from highest to lowest
+func Descending[T any, K cmp.Ordered](key func(item T) K) SortKey[T] {
+	return func(a T, b T) int {
+		return cmp.Compare(key(b), key(a))
+	}
+}
+
+// SortBy returns a copy of the slice sorted in ascending order of the value returned from the key function. The sort
+// is not guaranteed to be stable; see StableSortBy
+func SortBy[T any, K cmp.Ordered](list []T, key func(item T) K) []T {
+	out := slices.Clone(list)
+	slices.SortFunc(out, Ascending(key))
+	return out
+}
+
+// StableSortBy is like SortBy, but keeps the original order of elements with equal keys
+func StableSortBy[T any, K cmp.Ordered](list []T, key func(item T) K) []T {
+	out := slices.Clone(list)
+	slices.SortStableFunc(out, Ascending(key))
+	return out
+}
+
+// SortByKeys returns a copy of the slice sorted by each of the keys in turn, with later keys only being consulted when
+// all earlier keys are equal. The sort is stable, so elements equal on every key keep their original order
+func SortByKeys[T any](list []T, keys ...SortKey[T]) []T {
+	out := slices.Clone(list)
+	slices.SortStableFunc(out, func(a T, b T) int {
+		for _, key := range keys {
+			if c := key(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return out
+}
+
+// IsSortedBy returns true if the slice is in ascending order of the value returned from the key function
+func IsSortedBy[T any, K cmp.Ordered](list []T, key func(item T) K) bool {
+	return slices.IsSortedFunc(list, Ascending(key))
+}
+
+// TopN returns the n elements with the highest value returned from the key function, from highest to lowest. Only a
+// heap of n elements is maintained, rather than sorting the entire slice
+func TopN[T any, K cmp.Ordered](list []T, n int, key func(item T) K) []T {
+	return boundedSort(list, n, Descending(key))
+}
+
+// BottomN returns the n elements with the lowest value returned from the key function, from lowest to highest. Only a
+// heap of n elements is maintained, rather than sorting the entire slice
+func BottomN[T any, K cmp.Ordered](list []T, n int, key func(item T) K) []T {
+	return boundedSort(list, n, Ascending(key))
+}
+
+// boundedSort returns the first n elements of the slice in the order given by compare, without sorting the whole slice
+func boundedSort[T any](list []T, n int, compare SortKey[T]) []T {
+	if n <= 0 {
+		return []T{}
+	}
+
+	// The heap root is the element that sorts last, so it is the one evicted when a better element is found
+	h := &boundedHeap[T]{
+		less: func(a T, b T) bool { return compare(a, b) > 0 },
+	}
+	for _, item := range list {
+		if len(h.items) < n {
+			heap.Push(h, item)
+			continue
+		}
+		if compare(item, h.items[0]) < 0 {
+			h.items[0] = item
+			heap.Fix(h, 0)
+		}
+	}
+
+	out := make([]T, len(h.items))
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = heap.Pop(h).(T)
+	}
+	return out
+}
+
+type boundedHeap[T any] struct {
+	items []T
+	less  func(a T, b T) bool
+}
+
+func (h *boundedHeap[T]) Len() int           { return len(h.items) }
+func (h *boundedHeap[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *boundedHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *boundedHeap[T]) Push(x any)         { h.items = append(h.items, x.(T)) }
+func (h *boundedHeap[T]) Pop() any {
+	last := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return last
+}
@@ -0,0 +1,82 @@
+package hlp
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type sortPerson struct {
+	Name string
+	Age  int
+}
+
+func sortPeople() []sortPerson {
+	return []sortPerson{
+		{Name: "carol", Age: 30},
+		{Name: "alice", Age: 25},
+		{Name: "bob", Age: 30},
+		{Name: "dave", Age: 25},
+		{Name: "erin", Age: 40},
+	}
+}
+
+func personNames(people []sortPerson) []string {
+	return Map(people, func(item sortPerson, _ int) string { return item.Name })
+}
+
+func personAge(p sortPerson) int { return p.Age }
+
+func personName(p sortPerson) string { return p.Name }
+
+func TestSortBy(t *testing.T) {
+	input := sortPeople()
+	got := SortBy(input, personName)
+	require.Equal(t, []string{"alice", "bob", "carol", "dave", "erin"}, personNames(got))
+	require.Equal(t, sortPeople(), input, "input should not be modified")
+}
+
+func TestStableSortBy(t *testing.T) {
+	got := StableSortBy(sortPeople(), personAge)
+	require.Equal(t, []string{"alice", "dave", "carol", "bob", "erin"}, personNames(got))
+}
+
+func TestSortByKeys(t *testing.T) {
+	got := SortByKeys(sortPeople(), Descending(personAge), Ascending(personName))
+	require.Equal(t, []string{"erin", "bob", "carol", "alice", "dave"}, personNames(got))
+}
+
+func TestIsSortedBy(t *testing.T) {
+	require.False(t, IsSortedBy(sortPeople(), personAge))
+	require.True(t, IsSortedBy(StableSortBy(sortPeople(), personAge), personAge))
+	require.True(t, IsSortedBy([]sortPerson{}, personAge))
+}
+
+func TestTopN(t *testing.T) {
+	t.Run("smokes", func(t *testing.T) {
+		require.Equal(t, []int{6, 5, 4}, TopN(intSlice(), 3, func(item int) int { return item }))
+	})
+
+	t.Run("more than available", func(t *testing.T) {
+		require.Equal(t, []int{6, 5, 4, 3, 2, 1}, TopN(intSlice(), 10, func(item int) int { return item }))
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		require.Empty(t, TopN(intSlice(), 0, func(item int) int { return item }))
+	})
+
+	t.Run("matches full sort", func(t *testing.T) {
+		input := rand.New(rand.NewSource(1)).Perm(1000)
+		sorted := slices.Clone(input)
+		slices.Sort(sorted)
+		slices.Reverse(sorted)
+		require.Equal(t, sorted[:10], TopN(input, 10, func(item int) int { return item }))
+	})
+}
+
+func TestBottomN(t *testing.T) {
+	require.Equal(t, []int{1, 2}, BottomN(intSlice(), 2, func(item int) int { return item }))
+	require.Equal(t, []string{"alice", "dave"}, personNames(SortBy(BottomN(sortPeople(), 2, personAge), personName)))
+}
@@ -0,0 +1,125 @@
+package sqlx
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidArrayError = errors.New("invalid array literal")
+)
+
+// StringArray is a column type for one dimensional Postgres text arrays (text[], varchar[]). A NULL column scans to a
+// nil slice, and a nil slice is written as NULL. NULL elements cannot be represented, and so error when scanned
+type StringArray []string
+
+// Scan implements sql.Scanner, parsing a Postgres array literal such as `{a,"b c",d}`
+func (a *StringArray) Scan(src any) error {
+	var literal string
+	switch typed := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		literal = string(typed)
+	case string:
+		literal = typed
+	default:
+		return fmt.Errorf("%w: cannot scan %T into StringArray", ErrUnsupportedScanTypeError, src)
+	}
+
+	parsed, err := parseStringArray(literal)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, writing the array as a Postgres array literal with every element quoted
+func (a StringArray) Value() (driver.Value, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("{")
+	for i, elem := range a {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(`"`)
+		sb.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(elem))
+		sb.WriteString(`"`)
+	}
+	sb.WriteString("}")
+
+	return sb.String(), nil
+}
+
+func parseStringArray(literal string) (StringArray, error) {
+	if len(literal) < 2 || literal[0] != '{' || literal[len(literal)-1] != '}' {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidArrayError, literal)
+	}
+	body := literal[1 : len(literal)-1]
+
+	out := StringArray{}
+	if body == "" {
+		return out, nil
+	}
+
+	i := 0
+	for {
+		var elem strings.Builder
+		quoted := false
+
+		if i < len(body) && body[i] == '"' {
+			quoted = true
+			i += 1
+			for {
+				if i >= len(body) {
+					return nil, fmt.Errorf("%w: unterminated quote in %q", ErrInvalidArrayError, literal)
+				}
+				c := body[i]
+				if c == '\\' && i+1 < len(body) {
+					elem.WriteByte(body[i+1])
+					i += 2
+					continue
+				}
+				if c == '"' {
+					i += 1
+					break
+				}
+				elem.WriteByte(c)
+				i += 1
+			}
+		} else {
+			for i < len(body) && body[i] != ',' {
+				if body[i] == '{' || body[i] == '"' {
+					return nil, fmt.Errorf("%w: unexpected %q in %q", ErrInvalidArrayError, body[i], literal)
+				}
+				if body[i] == '\\' && i+1 < len(body) {
+					i += 1
+				}
+				elem.WriteByte(body[i])
+				i += 1
+			}
+		}
+
+		value := elem.String()
+		if !quoted && value == "NULL" {
+			return nil, fmt.Errorf("%w: NULL element at index %v", ErrInvalidArrayError, len(out))
+		}
+		out = append(out, value)
+
+		if i == len(body) {
+			return out, nil
+		}
+		if body[i] != ',' {
+			return nil, fmt.Errorf("%w: expected ',' in %q", ErrInvalidArrayError, literal)
+		}
+		i += 1
+	}
+}
@@ -0,0 +1,59 @@
+package sqlx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringArray(t *testing.T) {
+	t.Run("scan", func(t *testing.T) {
+		testCases := []struct {
+			name    string
+			src     any
+			want    StringArray
+			wantErr bool
+		}{
+			{name: "null", src: nil, want: nil},
+			{name: "empty", src: "{}", want: StringArray{}},
+			{name: "simple", src: []byte("{a,b,c}"), want: StringArray{"a", "b", "c"}},
+			{name: "quoted", src: `{"a b","c,d","e\"f","g\\h",""}`, want: StringArray{"a b", "c,d", `e"f`, `g\h`, ""}},
+			{name: "quoted null", src: `{"NULL"}`, want: StringArray{"NULL"}},
+			{name: "null element", src: "{a,NULL}", wantErr: true},
+			{name: "unterminated", src: `{"a}`, wantErr: true},
+			{name: "multi dimensional", src: "{{a},{b}}", wantErr: true},
+			{name: "not an array", src: "abc", wantErr: true},
+			{name: "unsupported type", src: 7, wantErr: true},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				var got StringArray
+				err := got.Scan(tc.src)
+				if tc.wantErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, tc.want, got)
+			})
+		}
+	})
+
+	t.Run("value round trips", func(t *testing.T) {
+		original := StringArray{"a b", "c,d", `e"f`, `g\h`, "", "NULL"}
+
+		value, err := original.Value()
+		require.NoError(t, err)
+		require.Equal(t, `{"a b","c,d","e\"f","g\\h","","NULL"}`, value)
+
+		var got StringArray
+		require.NoError(t, got.Scan(value))
+		require.Equal(t, original, got)
+	})
+
+	t.Run("nil value", func(t *testing.T) {
+		value, err := StringArray(nil).Value()
+		require.NoError(t, err)
+		require.Nil(t, value)
+	})
+}
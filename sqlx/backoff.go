@@ -0,0 +1,17 @@
+package sqlx
+
+import (
+	"github.com/nicjohnson145/hlp/wait"
+)
+
+// BackoffOpts are options used to configure how long to wait between retries, see wait.BackoffOpts
+type BackoffOpts = wait.BackoffOpts
+
+// BackoffStrategy is the algorithm used to space out retries, see wait.BackoffStrategy
+type BackoffStrategy = wait.BackoffStrategy
+
+const (
+	BackoffFibonacci   = wait.BackoffFibonacci
+	BackoffConstant    = wait.BackoffConstant
+	BackoffExponential = wait.BackoffExponential
+)
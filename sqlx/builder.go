@@ -0,0 +1,186 @@
+package sqlx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/nicjohnson145/hlp"
+)
+
+// Dialect is the flavor of SQL a QueryBuilder generates statements for
+type Dialect string
+
+const (
+	DialectPostgres Dialect = "postgres"
+	DialectMySQL    Dialect = "mysql"
+	DialectSQLite   Dialect = "sqlite"
+)
+
+var (
+	ErrUnknownDialectError    = errors.New("unknown dialect")
+	ErrUnknownPrimaryKeyError = errors.New("unknown primary key column")
+)
+
+// DialectForDriver returns the Dialect matching the given database/sql driver name, such as the one returned by
+// (*sqlx.DB).DriverName
+func DialectForDriver(driverName string) (Dialect, error) {
+	switch driverName {
+	case "postgres", "pgx", "pgx/v5", "pq":
+		return DialectPostgres, nil
+	case "mysql":
+		return DialectMySQL, nil
+	case "sqlite", "sqlite3":
+		return DialectSQLite, nil
+	default:
+		return "", fmt.Errorf("%w: %v", ErrUnknownDialectError, driverName)
+	}
+}
+
+// QueryBuilder generates CRUD statements for a `db` tagged struct, deriving column lists in the same manner as
+// StructScan. All statements use named parameters, and so are intended for use with the *Named* helpers such as
+// SelectNamedCtx and ExecNamedCtx
+type QueryBuilder[T any] struct {
+	dialect    Dialect
+	table      string
+	columns    []string
+	primaryKey []string
+}
+
+// NewQueryBuilder creates a QueryBuilder for the given dialect and table, keyed by the given primary key columns, of
+// which there must be at least one. T may not contain nested struct fields, as they do not map to a single column
+func NewQueryBuilder[T any](dialect Dialect, table string, primaryKey ...string) (*QueryBuilder[T], error) {
+	switch dialect {
+	case DialectPostgres, DialectMySQL, DialectSQLite:
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnknownDialectError, dialect)
+	}
+
+	columns, err := structColumns(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		var x T
+		return nil, fmt.Errorf("%w: %T has no db columns", ErrNoColumnsError, x)
+	}
+
+	if len(primaryKey) == 0 {
+		return nil, fmt.Errorf("%w: no primary key columns given", ErrUnknownPrimaryKeyError)
+	}
+	for _, key := range primaryKey {
+		if !slices.Contains(columns, key) {
+			return nil, fmt.Errorf("%w: %v", ErrUnknownPrimaryKeyError, key)
+		}
+	}
+
+	return &QueryBuilder[T]{
+		dialect:    dialect,
+		table:      table,
+		columns:    columns,
+		primaryKey: primaryKey,
+	}, nil
+}
+
+// Columns returns the column names derived from T
+func (q *QueryBuilder[T]) Columns() []string {
+	return slices.Clone(q.columns)
+}
+
+// Select generates a statement selecting all columns from the table
+func (q *QueryBuilder[T]) Select() string {
+	return fmt.Sprintf("SELECT %v FROM %v", q.columnList(q.columns), q.quote(q.table))
+}
+
+// SelectByPK generates a statement selecting all columns from the table for a single primary key
+func (q *QueryBuilder[T]) SelectByPK() string {
+	return fmt.Sprintf("%v WHERE %v", q.Select(), q.assignments(q.primaryKey, " AND "))
+}
+
+// Insert generates a statement inserting all columns into the table
+func (q *QueryBuilder[T]) Insert() string {
+	return fmt.Sprintf(
+		"INSERT INTO %v (%v) VALUES (%v)",
+		q.quote(q.table),
+		q.columnList(q.columns),
+		strings.Join(hlp.Map(q.columns, func(c string, _ int) string { return ":" + c }), ", "),
+	)
+}
+
+// UpdateByPK generates a statement updating all non primary key columns for a single primary key
+func (q *QueryBuilder[T]) UpdateByPK() string {
+	return fmt.Sprintf(
+		"UPDATE %v SET %v WHERE %v",
+		q.quote(q.table),
+		q.assignments(q.nonKeyColumns(), ", "),
+		q.assignments(q.primaryKey, " AND "),
+	)
+}
+
+// Upsert generates a statement inserting all columns into the table, updating all non primary key columns if a row
+// with the same primary key already exists
+func (q *QueryBuilder[T]) Upsert() string {
+	nonKey := q.nonKeyColumns()
+
+	if q.dialect == DialectMySQL {
+		if len(nonKey) == 0 {
+			nonKey = q.primaryKey[:1]
+		}
+		return fmt.Sprintf(
+			"%v ON DUPLICATE KEY UPDATE %v",
+			q.Insert(),
+			strings.Join(hlp.Map(nonKey, func(c string, _ int) string {
+				return fmt.Sprintf("%v = VALUES(%v)", q.quote(c), q.quote(c))
+			}), ", "),
+		)
+	}
+
+	conflict := fmt.Sprintf("%v ON CONFLICT (%v)", q.Insert(), q.columnList(q.primaryKey))
+	if len(nonKey) == 0 {
+		return conflict + " DO NOTHING"
+	}
+	return fmt.Sprintf(
+		"%v DO UPDATE SET %v",
+		conflict,
+		strings.Join(hlp.Map(nonKey, func(c string, _ int) string {
+			return fmt.Sprintf("%v = EXCLUDED.%v", q.quote(c), q.quote(c))
+		}), ", "),
+	)
+}
+
+func (q *QueryBuilder[T]) nonKeyColumns() []string {
+	return hlp.Filter(q.columns, func(c string, _ int) bool {
+		return !slices.Contains(q.primaryKey, c)
+	})
+}
+
+func (q *QueryBuilder[T]) columnList(columns []string) string {
+	return strings.Join(hlp.Map(columns, func(c string, _ int) string { return q.quote(c) }), ", ")
+}
+
+func (q *QueryBuilder[T]) assignments(columns []string, sep string) string {
+	return strings.Join(hlp.Map(columns, func(c string, _ int) string {
+		return fmt.Sprintf("%v = :%v", q.quote(c), c)
+	}), sep)
+}
+
+// quote quotes the given, possibly schema qualified, identifier for the dialect
+func (q *QueryBuilder[T]) quote(ident string) string {
+	return quoteIdent(q.dialect, ident)
+}
+
+// quoteIdent quotes the given, possibly schema qualified, identifier for the dialect
+func quoteIdent(dialect Dialect, ident string) string {
+	quoteChar := `"`
+	if dialect == DialectMySQL {
+		quoteChar = "`"
+	}
+
+	parts := strings.Split(ident, ".")
+	for i, part := range parts {
+		parts[i] = quoteChar + strings.ReplaceAll(part, quoteChar, quoteChar+quoteChar) + quoteChar
+	}
+	return strings.Join(parts, ".")
+}
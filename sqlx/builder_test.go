@@ -0,0 +1,144 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDialectForDriver(t *testing.T) {
+	got, err := DialectForDriver("pgx")
+	require.NoError(t, err)
+	require.Equal(t, DialectPostgres, got)
+
+	_, err = DialectForDriver("oracle")
+	require.ErrorIs(t, err, ErrUnknownDialectError)
+}
+
+func TestNewQueryBuilder(t *testing.T) {
+	t.Run("unknown primary key", func(t *testing.T) {
+		_, err := NewQueryBuilder[testUser](DialectPostgres, "users", "email")
+		require.ErrorIs(t, err, ErrUnknownPrimaryKeyError)
+	})
+
+	t.Run("no primary key", func(t *testing.T) {
+		_, err := NewQueryBuilder[testUser](DialectPostgres, "users")
+		require.ErrorIs(t, err, ErrUnknownPrimaryKeyError)
+	})
+
+	t.Run("no columns", func(t *testing.T) {
+		_, err := NewQueryBuilder[int](DialectPostgres, "users", "id")
+		require.ErrorIs(t, err, ErrNoColumnsError)
+	})
+
+	t.Run("nested struct", func(t *testing.T) {
+		_, err := NewQueryBuilder[testNestedColumns](DialectPostgres, "users", "id")
+		require.ErrorIs(t, err, ErrNestedStructError)
+	})
+
+	t.Run("unknown dialect", func(t *testing.T) {
+		_, err := NewQueryBuilder[testUser](Dialect("oracle"), "users", "id")
+		require.ErrorIs(t, err, ErrUnknownDialectError)
+	})
+}
+
+func TestQueryBuilder(t *testing.T) {
+	testCases := []struct {
+		name       string
+		dialect    Dialect
+		select_    string
+		selectByPK string
+		insert     string
+		updateByPK string
+		upsert     string
+	}{
+		{
+			name:       "postgres",
+			dialect:    DialectPostgres,
+			select_:    `SELECT "id", "name" FROM "app"."users"`,
+			selectByPK: `SELECT "id", "name" FROM "app"."users" WHERE "id" = :id`,
+			insert:     `INSERT INTO "app"."users" ("id", "name") VALUES (:id, :name)`,
+			updateByPK: `UPDATE "app"."users" SET "name" = :name WHERE "id" = :id`,
+			upsert:     `INSERT INTO "app"."users" ("id", "name") VALUES (:id, :name) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+		},
+		{
+			name:       "sqlite",
+			dialect:    DialectSQLite,
+			select_:    `SELECT "id", "name" FROM "app"."users"`,
+			selectByPK: `SELECT "id", "name" FROM "app"."users" WHERE "id" = :id`,
+			insert:     `INSERT INTO "app"."users" ("id", "name") VALUES (:id, :name)`,
+			updateByPK: `UPDATE "app"."users" SET "name" = :name WHERE "id" = :id`,
+			upsert:     `INSERT INTO "app"."users" ("id", "name") VALUES (:id, :name) ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name"`,
+		},
+		{
+			name:       "mysql",
+			dialect:    DialectMySQL,
+			select_:    "SELECT `id`, `name` FROM `app`.`users`",
+			selectByPK: "SELECT `id`, `name` FROM `app`.`users` WHERE `id` = :id",
+			insert:     "INSERT INTO `app`.`users` (`id`, `name`) VALUES (:id, :name)",
+			updateByPK: "UPDATE `app`.`users` SET `name` = :name WHERE `id` = :id",
+			upsert:     "INSERT INTO `app`.`users` (`id`, `name`) VALUES (:id, :name) ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			builder, err := NewQueryBuilder[testUser](tc.dialect, "app.users", "id")
+			require.NoError(t, err)
+
+			require.Equal(t, []string{"id", "name"}, builder.Columns())
+			require.Equal(t, tc.select_, builder.Select())
+			require.Equal(t, tc.selectByPK, builder.SelectByPK())
+			require.Equal(t, tc.insert, builder.Insert())
+			require.Equal(t, tc.updateByPK, builder.UpdateByPK())
+			require.Equal(t, tc.upsert, builder.Upsert())
+		})
+	}
+
+	t.Run("upsert with only key columns", func(t *testing.T) {
+		builder, err := NewQueryBuilder[testUser](DialectPostgres, "users", "id", "name")
+		require.NoError(t, err)
+		require.Equal(t, `INSERT INTO "users" ("id", "name") VALUES (:id, :name) ON CONFLICT ("id", "name") DO NOTHING`, builder.Upsert())
+	})
+
+	t.Run("executes through named helpers", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectExec(`INSERT INTO "users" ("id", "name") VALUES ($1, $2)`).
+			WithArgs(1, "alice").
+			WillReturnResult(0, 1)
+
+		builder, err := NewQueryBuilder[testUser](DialectPostgres, "users", "id")
+		require.NoError(t, err)
+
+		_, err = RequireExactExecNamedCtx(context.Background(), 1, db, builder.Insert(), testUser{ID: 1, Name: "alice"})
+		require.NoError(t, err)
+	})
+
+	t.Run("tag options and embedded structs", func(t *testing.T) {
+		type account struct {
+			testAudit
+			ID    int64  `db:"id"`
+			Email string `db:"email,omitempty"`
+		}
+
+		builder, err := NewQueryBuilder[account](DialectSQLite, "accounts", "id")
+		require.NoError(t, err)
+		require.Equal(t, []string{"created_by", "id", "email"}, builder.Columns())
+		require.Equal(t, `INSERT INTO "accounts" ("created_by", "id", "email") VALUES (:created_by, :id, :email)`, builder.Insert())
+
+		ctx := context.Background()
+		db := sqliteDB(t)
+		_, err = db.ExecContext(ctx, "CREATE TABLE accounts (id INTEGER PRIMARY KEY, email TEXT NOT NULL, created_by TEXT NOT NULL)")
+		require.NoError(t, err)
+
+		want := account{testAudit: testAudit{CreatedBy: "admin"}, ID: 1, Email: "a@example.com"}
+		_, err = RequireExactExecNamedCtx(ctx, 1, db, builder.Insert(), want)
+		require.NoError(t, err)
+		_, err = RequireExactExecNamedCtx(ctx, 1, db, builder.Upsert(), want)
+		require.NoError(t, err)
+
+		got, err := GetNamedCtx[account](ctx, db, builder.SelectByPK(), map[string]any{"id": 1})
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	})
+}
@@ -0,0 +1,83 @@
+package sqlx
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/reflectx"
+)
+
+var (
+	ErrNestedStructError = errors.New("nested struct fields are not supported")
+
+	columnMapper = reflectx.NewMapperFunc("db", sqlx.NameMapper)
+	columnCache  sync.Map
+	valuerType   = reflect.TypeFor[driver.Valuer]()
+)
+
+type cachedColumns struct {
+	columns []string
+	err     error
+}
+
+// structColumns returns the column names of the given struct type, as derived from its `db` tags by the same mapper
+// StructScan uses. Tag options are ignored, untagged fields use sqlx.NameMapper, fields tagged `db:"-"` are skipped and
+// untagged embedded structs are flattened. Nested struct fields (which sqlx maps to dotted paths such as `home.street`)
+// do not map to a single column, and so are an error unless they are scanned or valued directly, like time.Time.
+// Results are cached per type
+func structColumns(t reflect.Type) ([]string, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if cached, ok := columnCache.Load(t); ok {
+		c := cached.(cachedColumns)
+		return c.columns, c.err
+	}
+
+	columns := []string{}
+	var err error
+	if t.Kind() == reflect.Struct {
+		columns, err = appendColumns(columns, columnMapper.TypeMap(t).Tree.Children)
+	}
+	if err != nil {
+		columns = nil
+	}
+	columnCache.Store(t, cachedColumns{columns: columns, err: err})
+
+	return columns, err
+}
+
+func appendColumns(columns []string, fields []*reflectx.FieldInfo) ([]string, error) {
+	for _, field := range fields {
+		// Unexported and `db:"-"` fields have no entry
+		if field == nil {
+			continue
+		}
+
+		if isColumn(field.Field.Type) {
+			columns = append(columns, field.Name)
+			continue
+		}
+
+		if field.Embedded && field.Field.Tag.Get("db") == "" {
+			var err error
+			columns, err = appendColumns(columns, field.Children)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		return nil, fmt.Errorf("%w: field %v", ErrNestedStructError, field.Path)
+	}
+	return columns, nil
+}
+
+// isColumn reports if a field of the given type maps to a single column
+func isColumn(t reflect.Type) bool {
+	return isScalar(t) || t.Implements(valuerType) || reflect.PointerTo(t).Implements(valuerType)
+}
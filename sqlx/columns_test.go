@@ -0,0 +1,69 @@
+package sqlx
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testAudit struct {
+	CreatedBy string `db:"created_by"`
+}
+
+type testColumns struct {
+	testAudit
+	ID        int64 `db:"id"`
+	Name      string
+	Email     string `db:"email,omitempty"`
+	Nickname  Null[string]
+	CreatedAt time.Time `db:"created_at"`
+	Ignored   string    `db:"-"`
+	internal  string
+}
+
+type testAddress struct {
+	Street string `db:"street"`
+}
+
+type testNestedColumns struct {
+	ID   int64       `db:"id"`
+	Home testAddress `db:"home"`
+}
+
+type testTaggedEmbedColumns struct {
+	ID        int64 `db:"id"`
+	testAudit `db:"audit"`
+}
+
+func TestStructColumns(t *testing.T) {
+	want := []string{"created_by", "id", "name", "email", "nickname", "created_at"}
+
+	t.Run("smokes", func(t *testing.T) {
+		got, err := structColumns(reflect.TypeFor[testColumns]())
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+
+		got, err = structColumns(reflect.TypeFor[*testColumns]())
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	})
+
+	t.Run("non struct", func(t *testing.T) {
+		got, err := structColumns(reflect.TypeFor[int]())
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+
+	t.Run("nested struct", func(t *testing.T) {
+		_, err := structColumns(reflect.TypeFor[testNestedColumns]())
+		require.ErrorIs(t, err, ErrNestedStructError)
+		require.ErrorContains(t, err, "home")
+	})
+
+	t.Run("tagged embedded struct", func(t *testing.T) {
+		_, err := structColumns(reflect.TypeFor[testTaggedEmbedColumns]())
+		require.ErrorIs(t, err, ErrNestedStructError)
+	})
+}
@@ -5,42 +5,57 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/sethvargo/go-retry"
 	"github.com/go-logr/logr"
+	"github.com/nicjohnson145/hlp/wait"
 )
 
 // DBWaitOpts are options used to configure the behavior of WaitForDBConnectable
 type DBWaitOpts struct {
 	// Timeout is the amount of time to wait for the DB to connect before erroring, nil represents an indefinite wait
 	Timeout *time.Duration
-	// Logger is the logger to which to write connection errors, nil will result in no errors being logged
+	// Logger is the logger to which to write connection errors, nil will result in no errors being logged. Failed
+	// attempts that will be retried are logged at V(1), and only the final failure is logged as an error
 	Logger *logr.Logger
+	// Backoff configures the interval between connection attempts, the zero value is a fibonacci backoff with a 1s base
+	Backoff BackoffOpts
+	// MaxAttempts is the maximum number of connection attempts to make before erroring, 0 represents unlimited attempts
+	MaxAttempts uint64
+	// OnAttempt, if set, is called after every connection attempt with the 1-based attempt number and the resulting
+	// error, which is nil for the successful attempt
+	OnAttempt func(attempt int, err error)
 }
 
-// ContextPinger is an abstraction for unit testing. *sql.DB satisfies this interface and should be used when calling WaitForDBConnectable
-type ContextPinger interface {
-	PingContext(context.Context) error
-}
+// ContextPinger is an abstraction for unit testing. *sql.DB satisfies this interface and should be used when calling
+// WaitForDBConnectable
+type ContextPinger = wait.ContextPinger
 
 // WaitForDBConnectable waits for the given ContextPinger (satisfied by *sql.DB) to respond without error. See DBWaitOpts for configuration options
 func WaitForDBConnectable(db ContextPinger, opts DBWaitOpts) error {
-	ctx := context.Background()
-	cancel := func() {}
+	return WaitForDBConnectableCtx(context.Background(), db, opts)
+}
+
+// WaitForDBConnectableCtx is like WaitForDBConnectable, but the wait is also aborted when the given context is done. If
+// the wait is aborted, the returned error wraps both the cancellation cause and the last connection error. It is a
+// thin wrapper around wait.For with a single dependency named "database"
+func WaitForDBConnectableCtx(ctx context.Context, db ContextPinger, opts DBWaitOpts) error {
 	if opts.Timeout != nil {
-		ctx, cancel = context.WithTimeout(context.Background(), *opts.Timeout)
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *opts.Timeout)
+		defer cancel()
 	}
-	defer cancel()
-
-	if err := retry.Fibonacci(ctx, 1*time.Second, func(ctx context.Context) error {
-		err := db.PingContext(ctx)
-		if err != nil {
-			if opts.Logger != nil {
-				opts.Logger.Error(err, "error connecting to database")
-			}
-			return retry.RetryableError(err)
+
+	waitOpts := wait.Opts{
+		Backoff:     opts.Backoff,
+		MaxAttempts: opts.MaxAttempts,
+		Logger:      opts.Logger,
+	}
+	if opts.OnAttempt != nil {
+		waitOpts.OnAttempt = func(_ string, attempt int, err error) {
+			opts.OnAttempt(attempt, err)
 		}
-		return nil
-	}); err != nil {
+	}
+
+	if err := wait.For(ctx, waitOpts, wait.Dependency{Name: "database", Probe: wait.Pinger(db)}); err != nil {
 		return fmt.Errorf("unable to wait for db connectable: %w", err)
 	}
 
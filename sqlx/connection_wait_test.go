@@ -2,10 +2,12 @@ package sqlx
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/go-logr/logr/funcr"
 	"github.com/stretchr/testify/require"
 )
 
@@ -52,4 +54,100 @@ func TestWaitForDBConnectable(t *testing.T) {
 
 		require.NoError(t, <-errChan)
 	})
+
+	t.Run("max_attempts", func(t *testing.T) {
+		pinger := &mockPinger{ErrorTimes: -1}
+
+		attempts := []int{}
+		err := WaitForDBConnectable(pinger, DBWaitOpts{
+			Backoff:     BackoffOpts{Strategy: BackoffConstant, Base: time.Millisecond},
+			MaxAttempts: 3,
+			OnAttempt: func(attempt int, err error) {
+				require.Error(t, err)
+				attempts = append(attempts, attempt)
+			},
+		})
+		require.Error(t, err)
+		require.Equal(t, []int{1, 2, 3}, attempts)
+		require.Equal(t, 3, pinger.count)
+	})
+
+	t.Run("logs_retries_below_error", func(t *testing.T) {
+		pinger := &mockPinger{ErrorTimes: -1}
+
+		logs := []string{}
+		logger := funcr.New(func(prefix, args string) {
+			logs = append(logs, args)
+		}, funcr.Options{Verbosity: 1})
+
+		err := WaitForDBConnectable(pinger, DBWaitOpts{
+			Logger:      &logger,
+			Backoff:     BackoffOpts{Strategy: BackoffConstant, Base: time.Millisecond},
+			MaxAttempts: 2,
+		})
+		require.Error(t, err)
+		require.Len(t, logs, 3)
+		require.Contains(t, logs[0], `"level"=1`)
+		require.Contains(t, logs[1], `"level"=1`)
+		require.Contains(t, logs[2], `"msg"="dependency never became ready"`)
+		require.Contains(t, logs[2], `"error"=`)
+	})
+
+	t.Run("callback_sees_success", func(t *testing.T) {
+		pinger := &mockPinger{ErrorTimes: 2}
+
+		var lastAttempt int
+		var lastErr error
+		err := WaitForDBConnectable(pinger, DBWaitOpts{
+			Backoff: BackoffOpts{Strategy: BackoffExponential, Base: time.Millisecond, MaxInterval: 2 * time.Millisecond, Jitter: time.Millisecond},
+			OnAttempt: func(attempt int, err error) {
+				lastAttempt = attempt
+				lastErr = err
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, lastAttempt)
+		require.NoError(t, lastErr)
+	})
+}
+
+func TestWaitForDBConnectableCtx(t *testing.T) {
+	t.Run("cancelled", func(t *testing.T) {
+		pinger := &mockPinger{ErrorTimes: -1}
+		cause := errors.New("sigterm")
+
+		ctx, cancel := context.WithCancelCause(context.Background())
+		err := WaitForDBConnectableCtx(ctx, pinger, DBWaitOpts{
+			Backoff: BackoffOpts{Strategy: BackoffConstant, Base: time.Millisecond},
+			OnAttempt: func(attempt int, err error) {
+				if attempt == 2 {
+					cancel(cause)
+				}
+			},
+		})
+		require.ErrorIs(t, err, cause)
+		require.ErrorContains(t, err, "some error")
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		pinger := &mockPinger{ErrorTimes: -1}
+		timeout := 10 * time.Millisecond
+
+		err := WaitForDBConnectableCtx(context.Background(), pinger, DBWaitOpts{
+			Timeout: &timeout,
+			Backoff: BackoffOpts{Strategy: BackoffConstant, Base: time.Millisecond},
+		})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "some error")
+	})
+
+	t.Run("already cancelled", func(t *testing.T) {
+		pinger := &mockPinger{ErrorTimes: -1}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := WaitForDBConnectableCtx(ctx, pinger, DBWaitOpts{})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, 0, pinger.count)
+	})
 }
@@ -24,3 +24,27 @@ func SqlNullToPointer[T any](x sql.Null[T]) *T {
 	}
 	return &x.V
 }
+
+// ZeroToSqlNull converts a value to a sql.Null for that type, treating the zero value of the type as NULL
+func ZeroToSqlNull[T comparable](x T) sql.Null[T] {
+	var zero T
+	return sql.Null[T]{
+		V:     x,
+		Valid: x != zero,
+	}
+}
+
+// SqlNullToZero is the inverse of ZeroToSqlNull, converting a sql.Null to its value, or the zero value of the type when
+// NULL
+func SqlNullToZero[T any](x sql.Null[T]) T {
+	var zero T
+	return SqlNullToDefault(x, zero)
+}
+
+// SqlNullToDefault converts a sql.Null to its value, or the given default when NULL
+func SqlNullToDefault[T any](x sql.Null[T], defaultVal T) T {
+	if !x.Valid {
+		return defaultVal
+	}
+	return x.V
+}
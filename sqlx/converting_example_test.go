@@ -7,3 +7,8 @@ func ExamplePointerToSqlNull() {
 	fmt.Println(PointerToSqlNull(&x))
 	//Output: {7 true}
 }
+
+func ExampleZeroToSqlNull() {
+	fmt.Println(ZeroToSqlNull(""), ZeroToSqlNull("a"))
+	//Output: { false} {a true}
+}
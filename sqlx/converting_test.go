@@ -28,3 +28,16 @@ func TestSqlNullToPointer(t *testing.T) {
 		require.Equal(t, &x, SqlNullToPointer(sql.Null[int]{V: 7, Valid: true}))
 	})
 }
+
+func TestZeroToSqlNull(t *testing.T) {
+	require.Equal(t, sql.Null[string]{V: "a", Valid: true}, ZeroToSqlNull("a"))
+	require.Equal(t, sql.Null[string]{}, ZeroToSqlNull(""))
+	require.Equal(t, sql.Null[int]{}, ZeroToSqlNull(0))
+}
+
+func TestSqlNullToDefault(t *testing.T) {
+	require.Equal(t, 7, SqlNullToDefault(sql.Null[int]{V: 7, Valid: true}, 3))
+	require.Equal(t, 3, SqlNullToDefault(sql.Null[int]{V: 7}, 3))
+	require.Equal(t, "", SqlNullToZero(sql.Null[string]{V: "a"}))
+	require.Equal(t, "a", SqlNullToZero(sql.Null[string]{V: "a", Valid: true}))
+}
@@ -0,0 +1,60 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ExecNamedCtx executes a statement with named parameters, returning the result
+func ExecNamedCtx(ctx context.Context, db sqlx.ExtContext, query string, args any) (sql.Result, error) {
+	result, err := sqlx.NamedExecContext(ctx, db, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("error executing: %w", err)
+	}
+	return result, nil
+}
+
+// ExecCtx is exactly like ExecNamedCtx, but for statements that take positional arguments. Placeholders are rebound in
+// the same manner as SelectCtx
+func ExecCtx(ctx context.Context, db sqlx.ExtContext, query string, args ...any) (sql.Result, error) {
+	result, err := db.ExecContext(ctx, rebind(db, query, args), args...)
+	if err != nil {
+		return nil, fmt.Errorf("error executing: %w", err)
+	}
+	return result, nil
+}
+
+// RequireExactExecNamedCtx is like ExecNamedCtx, except it enforces that the number of rows affected matches an
+// expected value
+func RequireExactExecNamedCtx(ctx context.Context, expected int, db sqlx.ExtContext, query string, args any) (sql.Result, error) {
+	result, err := ExecNamedCtx(ctx, db, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return requireAffected(expected, result)
+}
+
+// RequireExactExecCtx is like ExecCtx, except it enforces that the number of rows affected matches an expected value
+func RequireExactExecCtx(ctx context.Context, expected int, db sqlx.ExtContext, query string, args ...any) (sql.Result, error) {
+	result, err := ExecCtx(ctx, db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return requireAffected(expected, result)
+}
+
+func requireAffected(expected int, result sql.Result) (sql.Result, error) {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, fmt.Errorf("error getting rows affected: %w", err)
+	}
+
+	if err := checkCount(expected, affected); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,37 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecCtx(t *testing.T) {
+	db, fake := newFakeDB(t, "postgres")
+	fake.ExpectExec("DELETE FROM users WHERE name = $1").WithArgs("alice").WillReturnResult(0, 1)
+
+	result, err := ExecCtx(context.Background(), db, "DELETE FROM users WHERE name = ?", "alice")
+	require.NoError(t, err)
+	affected, err := result.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), affected)
+}
+
+func TestRequireExactExecCtx(t *testing.T) {
+	t.Run("matches", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectExec("DELETE FROM users WHERE id = $1").WithArgs(1).WillReturnResult(0, 1)
+
+		_, err := RequireExactExecCtx(context.Background(), 1, db, "DELETE FROM users WHERE id = ?", 1)
+		require.NoError(t, err)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectExec("DELETE FROM users WHERE id = $1").WithArgs(1)
+
+		_, err := RequireExactExecNamedCtx(context.Background(), 1, db, "DELETE FROM users WHERE id = :id", map[string]any{"id": 1})
+		require.ErrorIs(t, err, ErrNotFoundError)
+	})
+}
@@ -0,0 +1,159 @@
+package sqlx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	defaultHealthInterval = 10 * time.Second
+	defaultHealthTimeout  = 5 * time.Second
+)
+
+var (
+	ErrHealthNotCheckedError = errors.New("health not yet checked")
+)
+
+// HealthMonitorOpts are options used to configure the behavior of a HealthMonitor
+type HealthMonitorOpts struct {
+	// Interval is the amount of time between pings, 0 or less defaults to 10s
+	Interval time.Duration
+	// Timeout is the amount of time a single ping may take before being considered failed, 0 or less defaults to 5s
+	Timeout time.Duration
+	// Logger is the logger to which to write health transitions, nil will result in no transitions being logged
+	Logger *logr.Logger
+}
+
+// HealthStatus is the result of a single health check
+type HealthStatus struct {
+	// Healthy is true if the last ping succeeded
+	Healthy bool
+	// Latency is how long the last ping took
+	Latency time.Duration
+	// Err is the error from the last ping, if any
+	Err error
+	// CheckedAt is when the last ping was started, the zero time if no ping has been performed
+	CheckedAt time.Time
+}
+
+// HealthMonitor periodically pings a ContextPinger (satisfied by *sql.DB), retaining the most recent result. It
+// implements http.Handler, responding 200 when healthy and 503 otherwise, making it suitable for readiness probes
+type HealthMonitor struct {
+	pinger ContextPinger
+	opts   HealthMonitorOpts
+
+	mu     sync.RWMutex
+	status HealthStatus
+}
+
+// NewHealthMonitor creates a HealthMonitor for the given pinger. See HealthMonitorOpts for configuration options. No
+// pings are performed until Run or Check is called
+func NewHealthMonitor(pinger ContextPinger, opts HealthMonitorOpts) *HealthMonitor {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultHealthInterval
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultHealthTimeout
+	}
+
+	return &HealthMonitor{
+		pinger: pinger,
+		opts:   opts,
+		status: HealthStatus{
+			Err: ErrHealthNotCheckedError,
+		},
+	}
+}
+
+// Run checks health immediately, and then on every interval until the given context is cancelled. It blocks, and so
+// is generally called in its own goroutine
+func (h *HealthMonitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.opts.Interval)
+	defer ticker.Stop()
+
+	for ctx.Err() == nil {
+		h.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check performs a single ping, records and returns its result. If the given context is cancelled, such as on
+// shutdown, the result is returned but neither recorded nor logged, as it says nothing about the database
+func (h *HealthMonitor) Check(ctx context.Context) HealthStatus {
+	pingCtx, cancel := context.WithTimeout(ctx, h.opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := h.pinger.PingContext(pingCtx)
+	status := HealthStatus{
+		Healthy:   err == nil,
+		Latency:   time.Since(start),
+		Err:       err,
+		CheckedAt: start,
+	}
+	if ctx.Err() != nil {
+		return status
+	}
+
+	h.mu.Lock()
+	previous := h.status
+	h.status = status
+	h.mu.Unlock()
+
+	if h.opts.Logger != nil && (previous.Healthy != status.Healthy || previous.CheckedAt.IsZero()) {
+		if status.Healthy {
+			h.opts.Logger.Info("database is healthy", "latency", status.Latency)
+		} else {
+			h.opts.Logger.Error(status.Err, "database is unhealthy", "latency", status.Latency)
+		}
+	}
+
+	return status
+}
+
+// Status returns the result of the most recent check
+func (h *HealthMonitor) Status() HealthStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.status
+}
+
+type healthResponse struct {
+	Healthy   bool      `json:"healthy"`
+	LatencyMS float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// ServeHTTP reports the most recent status as JSON, with a 200 status code when healthy and 503 otherwise
+func (h *HealthMonitor) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	status := h.Status()
+
+	resp := healthResponse{
+		Healthy:   status.Healthy,
+		LatencyMS: float64(status.Latency) / float64(time.Millisecond),
+		CheckedAt: status.CheckedAt,
+	}
+	if status.Err != nil {
+		resp.Error = status.Err.Error()
+	}
+
+	code := http.StatusOK
+	if !status.Healthy {
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
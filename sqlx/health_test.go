@@ -0,0 +1,156 @@
+package sqlx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/require"
+)
+
+type funcPinger func(context.Context) error
+
+func (f funcPinger) PingContext(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestHealthMonitor(t *testing.T) {
+	t.Run("not checked", func(t *testing.T) {
+		monitor := NewHealthMonitor(funcPinger(func(context.Context) error { return nil }), HealthMonitorOpts{})
+
+		status := monitor.Status()
+		require.False(t, status.Healthy)
+		require.ErrorIs(t, status.Err, ErrHealthNotCheckedError)
+	})
+
+	t.Run("transitions", func(t *testing.T) {
+		var failing atomic.Bool
+		monitor := NewHealthMonitor(funcPinger(func(context.Context) error {
+			if failing.Load() {
+				return errWork
+			}
+			return nil
+		}), HealthMonitorOpts{})
+
+		require.True(t, monitor.Check(context.Background()).Healthy)
+		require.True(t, monitor.Status().Healthy)
+
+		failing.Store(true)
+		status := monitor.Check(context.Background())
+		require.False(t, status.Healthy)
+		require.ErrorIs(t, status.Err, errWork)
+		require.Equal(t, status, monitor.Status())
+	})
+
+	t.Run("logs transitions", func(t *testing.T) {
+		logs := []string{}
+		logger := funcr.New(func(prefix, args string) {
+			logs = append(logs, args)
+		}, funcr.Options{})
+
+		var failing atomic.Bool
+		monitor := NewHealthMonitor(funcPinger(func(context.Context) error {
+			if failing.Load() {
+				return errWork
+			}
+			return nil
+		}), HealthMonitorOpts{Logger: &logger})
+
+		monitor.Check(context.Background())
+		monitor.Check(context.Background())
+		failing.Store(true)
+		monitor.Check(context.Background())
+		monitor.Check(context.Background())
+		failing.Store(false)
+		monitor.Check(context.Background())
+
+		require.Len(t, logs, 3)
+		require.Contains(t, logs[0], `"msg"="database is healthy"`)
+		require.Contains(t, logs[1], `"msg"="database is unhealthy"`)
+		require.Contains(t, logs[1], errWork.Error())
+		require.Contains(t, logs[2], `"msg"="database is healthy"`)
+	})
+
+	t.Run("ignores cancelled checks", func(t *testing.T) {
+		logs := []string{}
+		logger := funcr.New(func(prefix, args string) {
+			logs = append(logs, args)
+		}, funcr.Options{})
+
+		monitor := NewHealthMonitor(funcPinger(func(ctx context.Context) error {
+			return ctx.Err()
+		}), HealthMonitorOpts{Logger: &logger})
+		monitor.Check(context.Background())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		status := monitor.Check(ctx)
+		require.ErrorIs(t, status.Err, context.Canceled)
+		require.True(t, monitor.Status().Healthy)
+		require.Len(t, logs, 1)
+	})
+
+	t.Run("negative interval", func(t *testing.T) {
+		monitor := NewHealthMonitor(funcPinger(func(context.Context) error { return nil }), HealthMonitorOpts{Interval: -time.Second})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.NotPanics(t, func() { monitor.Run(ctx) })
+	})
+
+	t.Run("http handler", func(t *testing.T) {
+		var failing atomic.Bool
+		monitor := NewHealthMonitor(funcPinger(func(context.Context) error {
+			if failing.Load() {
+				return errWork
+			}
+			return nil
+		}), HealthMonitorOpts{})
+
+		monitor.Check(context.Background())
+		rec := httptest.NewRecorder()
+		monitor.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		failing.Store(true)
+		monitor.Check(context.Background())
+		rec = httptest.NewRecorder()
+		monitor.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+		var body healthResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&body))
+		require.False(t, body.Healthy)
+		require.Equal(t, errWork.Error(), body.Error)
+	})
+
+	t.Run("run stops on cancel", func(t *testing.T) {
+		var count atomic.Int64
+		monitor := NewHealthMonitor(funcPinger(func(context.Context) error {
+			count.Add(1)
+			return nil
+		}), HealthMonitorOpts{Interval: time.Millisecond})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			monitor.Run(ctx)
+			close(done)
+		}()
+
+		require.Eventually(t, func() bool { return count.Load() >= 3 }, time.Second, time.Millisecond)
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("monitor did not stop")
+		}
+		require.True(t, monitor.Status().Healthy)
+	})
+}
@@ -0,0 +1,80 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nicjohnson145/hlp"
+)
+
+const (
+	// PostgresMaxParams is the maximum number of bind parameters Postgres accepts in a single statement
+	PostgresMaxParams = 65535
+)
+
+var (
+	ErrNoColumnsError = errors.New("no columns")
+)
+
+// BulkInsertNamedCtx inserts all of the given `db` tagged structs into the given table. Items are inserted in batches
+// sized so that no single statement exceeds maxParams bind parameters (see PostgresMaxParams). The table and column
+// names are quoted for the dialect of db's driver, see DialectForDriver. The total number of rows affected is
+// returned. Batches are not executed atomically, see BulkInsertNamedTxCtx for that
+func BulkInsertNamedCtx[T any](ctx context.Context, db sqlx.ExtContext, table string, items []T, maxParams int) (int64, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	columns, err := structColumns(reflect.TypeFor[T]())
+	if err != nil {
+		return 0, err
+	}
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("%w: %T has no db columns", ErrNoColumnsError, items[0])
+	}
+
+	batchSize := maxParams / len(columns)
+	if batchSize <= 0 {
+		return 0, fmt.Errorf("max params of %v is less than the %v columns in a single row", maxParams, len(columns))
+	}
+
+	dialect, err := DialectForDriver(db.DriverName())
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %v (%v) VALUES (:%v)",
+		quoteIdent(dialect, table),
+		strings.Join(hlp.Map(columns, func(c string, _ int) string { return quoteIdent(dialect, c) }), ", "),
+		strings.Join(columns, ", :"),
+	)
+
+	var total int64
+	for _, batch := range hlp.Batch(items, batchSize) {
+		result, err := sqlx.NamedExecContext(ctx, db, query, batch)
+		if err != nil {
+			return total, fmt.Errorf("error inserting batch: %w", err)
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return total, fmt.Errorf("error getting rows affected: %w", err)
+		}
+		total += affected
+	}
+
+	return total, nil
+}
+
+// BulkInsertNamedTxCtx is like BulkInsertNamedCtx, except all batches are executed within a single transaction, so
+// either every item is inserted or none are
+func BulkInsertNamedTxCtx[T any](ctx context.Context, db *sqlx.DB, table string, items []T, maxParams int) (int64, error) {
+	return WithTransactionReturningCtx(ctx, db, nil, func(tx *sqlx.Tx) (int64, error) {
+		return BulkInsertNamedCtx(ctx, tx, table, items, maxParams)
+	})
+}
@@ -0,0 +1,96 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkInsertNamedCtx(t *testing.T) {
+	users := []testUser{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}, {ID: 3, Name: "carol"}}
+
+	t.Run("batches by param count", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectExec(`INSERT INTO "users" ("id", "name") VALUES ($1, $2),($3, $4)`).
+			WithArgs(1, "alice", 2, "bob").
+			WillReturnResult(0, 2)
+		fake.ExpectExec(`INSERT INTO "users" ("id", "name") VALUES ($1, $2)`).
+			WithArgs(3, "carol").
+			WillReturnResult(0, 1)
+
+		got, err := BulkInsertNamedCtx(context.Background(), db, "users", users, 5)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), got)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		db, _ := newFakeDB(t, "postgres")
+
+		got, err := BulkInsertNamedCtx[testUser](context.Background(), db, "users", nil, PostgresMaxParams)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), got)
+	})
+
+	t.Run("too few params", func(t *testing.T) {
+		db, _ := newFakeDB(t, "postgres")
+
+		_, err := BulkInsertNamedCtx(context.Background(), db, "users", users, 1)
+		require.Error(t, err)
+	})
+
+	t.Run("negative params", func(t *testing.T) {
+		db, _ := newFakeDB(t, "postgres")
+
+		_, err := BulkInsertNamedCtx(context.Background(), db, "users", users, -10)
+		require.Error(t, err)
+	})
+
+	t.Run("quotes identifiers", func(t *testing.T) {
+		type order struct {
+			ID   int64  `db:"id"`
+			User string `db:"user"`
+		}
+
+		db, fake := newFakeDB(t, "mysql")
+		fake.ExpectExec("INSERT INTO `app`.`order` (`id`, `user`) VALUES (?, ?)").
+			WithArgs(1, "alice").
+			WillReturnResult(0, 1)
+
+		got, err := BulkInsertNamedCtx(context.Background(), db, "app.order", []order{{ID: 1, User: "alice"}}, 10)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), got)
+	})
+
+	t.Run("unknown dialect", func(t *testing.T) {
+		db, _ := newFakeDB(t, "oracle")
+
+		_, err := BulkInsertNamedCtx(context.Background(), db, "users", users, 10)
+		require.ErrorIs(t, err, ErrUnknownDialectError)
+	})
+
+	t.Run("no columns", func(t *testing.T) {
+		db, _ := newFakeDB(t, "postgres")
+
+		_, err := BulkInsertNamedCtx(context.Background(), db, "numbers", []int{1}, 1)
+		require.ErrorIs(t, err, ErrNoColumnsError)
+	})
+
+	t.Run("nested struct", func(t *testing.T) {
+		db, _ := newFakeDB(t, "postgres")
+
+		_, err := BulkInsertNamedCtx(context.Background(), db, "users", []testNestedColumns{{ID: 1}}, 10)
+		require.ErrorIs(t, err, ErrNestedStructError)
+	})
+}
+
+func TestBulkInsertNamedTxCtx(t *testing.T) {
+	db, fake := newFakeDB(t, "postgres")
+	fake.ExpectBegin()
+	fake.ExpectExec(`INSERT INTO "users" ("id", "name") VALUES ($1, $2)`).WithArgs(1, "")
+	fake.ExpectExec(`INSERT INTO "users" ("id", "name") VALUES ($1, $2)`).WithArgs(2, "").WillReturnError(errWork)
+	fake.ExpectRollback()
+
+	_, err := BulkInsertNamedTxCtx(context.Background(), db, "users", []testUser{{ID: 1}, {ID: 2}}, 2)
+	require.ErrorIs(t, err, errWork)
+}
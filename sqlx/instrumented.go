@@ -0,0 +1,215 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	// RedactedArg is the value substituted for redacted arguments
+	RedactedArg = "[REDACTED]"
+)
+
+// QueryEvent describes a single statement executed through an InstrumentedExt
+type QueryEvent struct {
+	// Query is the statement as sent to the driver
+	Query string
+	// Args are the bound arguments, after redaction
+	Args []any
+	// Duration is how long the driver took to execute the statement. For queries run through the helpers in this
+	// package this includes reading the rows, otherwise it excludes them
+	Duration time.Duration
+	// RowsAffected is the number of rows affected by an exec, or -1 for queries and failed statements
+	RowsAffected int64
+	// RowsReturned is the number of rows read from a query run through the helpers in this package, such as
+	// SelectNamedCtx or GetCtx, or -1 for execs and queries run directly against the InstrumentedExt
+	RowsReturned int64
+	// Err is the error returned from the driver, if any
+	Err error
+	// Slow is true if Duration met or exceeded the configured slow query threshold
+	Slow bool
+}
+
+// QueryHook receives a QueryEvent for every statement executed through an InstrumentedExt, such as for forwarding
+// measurements to a metrics system
+type QueryHook interface {
+	OnQuery(ctx context.Context, event QueryEvent)
+}
+
+// QueryHookFunc adapts a function to a QueryHook
+type QueryHookFunc func(ctx context.Context, event QueryEvent)
+
+func (f QueryHookFunc) OnQuery(ctx context.Context, event QueryEvent) {
+	f(ctx, event)
+}
+
+// RedactFunc returns the value to report in place of the argument at the given index of the given query
+type RedactFunc func(query string, index int, arg any) any
+
+// RedactAll is a RedactFunc that redacts every argument
+func RedactAll(_ string, _ int, _ any) any {
+	return RedactedArg
+}
+
+// RedactMatching creates a RedactFunc that redacts every argument of queries matching the given expression, such as
+// `(?i)password`
+func RedactMatching(exp *regexp.Regexp) RedactFunc {
+	return func(query string, _ int, arg any) any {
+		if exp.MatchString(query) {
+			return RedactedArg
+		}
+		return arg
+	}
+}
+
+// InstrumentOpts are options used to configure the behavior of an InstrumentedExt
+type InstrumentOpts struct {
+	// Logger is the logger to which to write statements. Errors are logged as errors, slow statements at V(0), and all
+	// others at V(1). nil will result in nothing being logged
+	Logger *logr.Logger
+	// SlowThreshold is the duration at or above which a statement is considered slow, 0 disables slow detection
+	SlowThreshold time.Duration
+	// Redact, if set, is applied to every argument before it is logged or passed to hooks
+	Redact RedactFunc
+	// Hooks are called, in order, after every statement
+	Hooks []QueryHook
+}
+
+// InstrumentedExt wraps a sqlx.ExtContext (such as *sqlx.DB or *sqlx.Tx), timing and reporting every statement executed
+// through it. It satisfies sqlx.ExtContext itself, and so can be passed to any of the helpers in this package. Queries
+// run through those helpers are reported once their rows have been read, so that the event includes RowsReturned.
+//
+// The transaction helpers such as WithTransactionReturning take a *sqlx.DB, so the *sqlx.Tx given to the work function
+// is not instrumented. Wrap it with Instrument (or Wrap) to report the statements executed inside the transaction
+type InstrumentedExt struct {
+	sqlx.ExtContext
+
+	opts InstrumentOpts
+}
+
+// Instrument wraps the given sqlx.ExtContext. See InstrumentOpts for configuration options
+func Instrument(db sqlx.ExtContext, opts InstrumentOpts) *InstrumentedExt {
+	return &InstrumentedExt{
+		ExtContext: db,
+		opts:       opts,
+	}
+}
+
+// Wrap instruments another sqlx.ExtContext with the same options, such as the *sqlx.Tx of a transaction opened from
+// the wrapped *sqlx.DB
+func (i *InstrumentedExt) Wrap(db sqlx.ExtContext) *InstrumentedExt {
+	return Instrument(db, i.opts)
+}
+
+func (i *InstrumentedExt) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := i.ExtContext.QueryContext(ctx, query, args...)
+	i.report(ctx, query, args, time.Since(start), -1, -1, err)
+	return rows, err
+}
+
+func (i *InstrumentedExt) QueryxContext(ctx context.Context, query string, args ...any) (*sqlx.Rows, error) {
+	start := time.Now()
+	rows, err := i.ExtContext.QueryxContext(ctx, query, args...)
+	if tracker, ok := ctx.Value(rowTrackerKey{}).(*rowTracker); ok && err == nil && tracker.finish == nil {
+		tracker.finish = func(rowsReturned int64, err error) {
+			i.report(ctx, query, args, time.Since(start), -1, rowsReturned, err)
+		}
+		return rows, nil
+	}
+	i.report(ctx, query, args, time.Since(start), -1, -1, err)
+	return rows, err
+}
+
+func (i *InstrumentedExt) QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row {
+	start := time.Now()
+	row := i.ExtContext.QueryRowxContext(ctx, query, args...)
+	i.report(ctx, query, args, time.Since(start), -1, -1, row.Err())
+	return row
+}
+
+func (i *InstrumentedExt) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	result, err := i.ExtContext.ExecContext(ctx, query, args...)
+	duration := time.Since(start)
+
+	affected := int64(-1)
+	if err == nil {
+		if n, affectedErr := result.RowsAffected(); affectedErr == nil {
+			affected = n
+		}
+	}
+	i.report(ctx, query, args, duration, affected, -1, err)
+
+	return result, err
+}
+
+func (i *InstrumentedExt) report(ctx context.Context, query string, args []any, duration time.Duration, affected int64, returned int64, err error) {
+	event := QueryEvent{
+		Query:        query,
+		Args:         args,
+		Duration:     duration,
+		RowsAffected: affected,
+		RowsReturned: returned,
+		Err:          err,
+		Slow:         i.opts.SlowThreshold > 0 && duration >= i.opts.SlowThreshold,
+	}
+	if i.opts.Redact != nil {
+		event.Args = make([]any, len(args))
+		for idx, arg := range args {
+			event.Args[idx] = i.opts.Redact(query, idx, arg)
+		}
+	}
+
+	if i.opts.Logger != nil {
+		keysAndValues := []any{"query", event.Query, "args", event.Args, "duration", event.Duration}
+		if event.RowsAffected >= 0 {
+			keysAndValues = append(keysAndValues, "rows_affected", event.RowsAffected)
+		}
+		if event.RowsReturned >= 0 {
+			keysAndValues = append(keysAndValues, "rows_returned", event.RowsReturned)
+		}
+
+		switch {
+		case event.Err != nil:
+			i.opts.Logger.Error(event.Err, "error executing query", keysAndValues...)
+		case event.Slow:
+			i.opts.Logger.Info("slow query", keysAndValues...)
+		default:
+			i.opts.Logger.V(1).Info("executed query", keysAndValues...)
+		}
+	}
+
+	for _, hook := range i.opts.Hooks {
+		hook.OnQuery(ctx, event)
+	}
+}
+
+type rowTrackerKey struct{}
+
+// rowTracker lets the helpers in this package tell an InstrumentedExt when they have finished reading the rows of a
+// query, so the event can be reported with the number of rows returned. Only the first query made with the tracking
+// context is tracked
+type rowTracker struct {
+	finish func(rowsReturned int64, err error)
+}
+
+// trackRows returns a context that defers the reporting of the first query made through an InstrumentedExt until done
+// is called on the returned tracker. It has no effect on uninstrumented queries
+func trackRows(ctx context.Context) (context.Context, *rowTracker) {
+	tracker := &rowTracker{}
+	return context.WithValue(ctx, rowTrackerKey{}, tracker), tracker
+}
+
+func (t *rowTracker) done(rowsReturned int64, err error) {
+	if t.finish == nil {
+		return
+	}
+	t.finish(rowsReturned, err)
+	t.finish = func(int64, error) {}
+}
@@ -0,0 +1,136 @@
+package sqlx
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentedExt(t *testing.T) {
+	collect := func(events *[]QueryEvent) QueryHook {
+		return QueryHookFunc(func(_ context.Context, event QueryEvent) {
+			*events = append(*events, event)
+		})
+	}
+
+	t.Run("reports through helpers", func(t *testing.T) {
+		raw, fake := newFakeDB(t, "postgres")
+		expectUsers(fake, "SELECT id, name FROM users WHERE id > $1")
+		fake.ExpectExec("DELETE FROM users WHERE id > $1").WillReturnResult(0, 2)
+
+		events := []QueryEvent{}
+		db := Instrument(raw, InstrumentOpts{Hooks: []QueryHook{collect(&events)}})
+
+		users, err := SelectNamedCtx[testUser](context.Background(), db, "SELECT id, name FROM users WHERE id > :id", map[string]any{"id": 0})
+		require.NoError(t, err)
+		require.Len(t, users, 3)
+
+		_, err = ExecCtx(context.Background(), db, "DELETE FROM users WHERE id > ?", 1)
+		require.NoError(t, err)
+
+		require.Len(t, events, 2)
+		require.Equal(t, "SELECT id, name FROM users WHERE id > $1", events[0].Query)
+		require.Equal(t, []any{0}, events[0].Args)
+		require.Equal(t, int64(-1), events[0].RowsAffected)
+		require.Equal(t, int64(3), events[0].RowsReturned)
+		require.Equal(t, "DELETE FROM users WHERE id > $1", events[1].Query)
+		require.Equal(t, int64(2), events[1].RowsAffected)
+		require.Equal(t, int64(-1), events[1].RowsReturned)
+	})
+
+	t.Run("reports rows once read", func(t *testing.T) {
+		raw, fake := newFakeDB(t, "postgres")
+		expectUsers(fake, "SELECT id, name FROM users")
+		expectUsers(fake, "SELECT id, name FROM users")
+		expectUsers(fake, "SELECT id, name FROM users")
+
+		events := []QueryEvent{}
+		db := Instrument(raw, InstrumentOpts{Hooks: []QueryHook{collect(&events)}})
+
+		for _, err := range SelectNamedSeqCtx[testUser](context.Background(), db, "SELECT id, name FROM users", map[string]any{}) {
+			require.NoError(t, err)
+			require.Empty(t, events, "event should not be reported until the rows are read")
+		}
+		require.Len(t, events, 1)
+		require.Equal(t, int64(3), events[0].RowsReturned)
+
+		_, err := GetCtx[testUser](context.Background(), db, "SELECT id, name FROM users")
+		require.ErrorIs(t, err, ErrUnexpectedRowCountError)
+		require.Len(t, events, 2)
+		require.Equal(t, int64(2), events[1].RowsReturned)
+		require.ErrorIs(t, events[1].Err, ErrUnexpectedRowCountError)
+
+		rows, err := db.QueryxContext(context.Background(), "SELECT id, name FROM users")
+		require.NoError(t, err)
+		require.NoError(t, rows.Close())
+		require.Len(t, events, 3)
+		require.Equal(t, int64(-1), events[2].RowsReturned)
+	})
+
+	t.Run("wraps transactions", func(t *testing.T) {
+		raw, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin()
+		fake.ExpectExec("DELETE FROM users").WillReturnResult(0, 1)
+		fake.ExpectCommit()
+
+		events := []QueryEvent{}
+		db := Instrument(raw, InstrumentOpts{Hooks: []QueryHook{collect(&events)}})
+
+		err := WithTransaction(raw, func(tx *sqlx.Tx) error {
+			_, err := ExecCtx(context.Background(), db.Wrap(tx), "DELETE FROM users")
+			return err
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, "DELETE FROM users", events[0].Query)
+	})
+
+	t.Run("redacts and flags slow queries", func(t *testing.T) {
+		raw, fake := newFakeDB(t, "postgres")
+		fake.ExpectExec("UPDATE users SET password = $1 WHERE id = $2").
+			WillDelayFor(5*time.Millisecond).
+			WillReturnResult(0, 1)
+
+		logs := []string{}
+		logger := funcr.New(func(prefix, args string) {
+			logs = append(logs, args)
+		}, funcr.Options{Verbosity: 1})
+
+		events := []QueryEvent{}
+		db := Instrument(raw, InstrumentOpts{
+			Logger:        &logger,
+			SlowThreshold: time.Millisecond,
+			Redact:        RedactMatching(regexp.MustCompile(`(?i)password`)),
+			Hooks:         []QueryHook{collect(&events)},
+		})
+
+		_, err := ExecCtx(context.Background(), db, "UPDATE users SET password = ? WHERE id = ?", "hunter2", 1)
+		require.NoError(t, err)
+
+		require.Len(t, events, 1)
+		require.True(t, events[0].Slow)
+		require.Equal(t, []any{RedactedArg, RedactedArg}, events[0].Args)
+		require.Len(t, logs, 1)
+		require.Contains(t, logs[0], `"msg"="slow query"`)
+		require.NotContains(t, logs[0], "hunter2")
+	})
+
+	t.Run("reports errors", func(t *testing.T) {
+		raw, fake := newFakeDB(t, "postgres")
+		fake.ExpectQuery("SELECT id, name FROM users WHERE id = $1").WillReturnError(errWork)
+
+		events := []QueryEvent{}
+		db := Instrument(raw, InstrumentOpts{Redact: RedactAll, Hooks: []QueryHook{collect(&events)}})
+
+		_, err := GetCtx[testUser](context.Background(), db, "SELECT id, name FROM users WHERE id = ?", 1)
+		require.ErrorIs(t, err, errWork)
+		require.Len(t, events, 1)
+		require.ErrorIs(t, events[0].Err, errWork)
+		require.Equal(t, []any{RedactedArg}, events[0].Args)
+	})
+}
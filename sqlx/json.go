@@ -0,0 +1,72 @@
+package sqlx
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnsupportedScanTypeError = errors.New("unsupported scan type")
+)
+
+// JSON is a nullable column type for JSON/JSONB columns, storing the held value marshalled to JSON
+type JSON[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NewJSON returns a valid JSON holding the given value
+func NewJSON[T any](x T) JSON[T] {
+	return JSON[T]{V: x, Valid: true}
+}
+
+// Scan implements sql.Scanner, accepting []byte and string driver values
+func (j *JSON[T]) Scan(src any) error {
+	var data []byte
+	switch typed := src.(type) {
+	case nil:
+		*j = JSON[T]{}
+		return nil
+	case []byte:
+		data = typed
+	case string:
+		data = []byte(typed)
+	default:
+		return fmt.Errorf("%w: cannot scan %T into JSON", ErrUnsupportedScanTypeError, src)
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("error unmarshalling JSON: %w", err)
+	}
+	*j = NewJSON(v)
+	return nil
+}
+
+// Value implements driver.Valuer, writing the held value as a JSON string
+func (j JSON[T]) Value() (driver.Value, error) {
+	if !j.Valid {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(j.V)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+func (j JSON[T]) MarshalJSON() ([]byte, error) {
+	return marshalNullJSON(j.V, j.Valid)
+}
+
+func (j *JSON[T]) UnmarshalJSON(data []byte) error {
+	v, valid, err := unmarshalNullJSON[T](data)
+	if err != nil {
+		return err
+	}
+	*j = JSON[T]{V: v, Valid: valid}
+	return nil
+}
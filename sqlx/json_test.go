@@ -0,0 +1,69 @@
+package sqlx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSettings struct {
+	Theme string   `json:"theme"`
+	Tags  []string `json:"tags"`
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("scan", func(t *testing.T) {
+		var fromBytes JSON[testSettings]
+		require.NoError(t, fromBytes.Scan([]byte(`{"theme": "dark", "tags": ["a"]}`)))
+		require.Equal(t, NewJSON(testSettings{Theme: "dark", Tags: []string{"a"}}), fromBytes)
+
+		var fromString JSON[testSettings]
+		require.NoError(t, fromString.Scan(`{"theme": "light"}`))
+		require.Equal(t, NewJSON(testSettings{Theme: "light"}), fromString)
+
+		fromNull := NewJSON(testSettings{Theme: "dark"})
+		require.NoError(t, fromNull.Scan(nil))
+		require.Equal(t, JSON[testSettings]{}, fromNull)
+
+		var invalid JSON[testSettings]
+		require.Error(t, invalid.Scan(`{`))
+		require.ErrorIs(t, invalid.Scan(7), ErrUnsupportedScanTypeError)
+	})
+
+	t.Run("value", func(t *testing.T) {
+		value, err := NewJSON(testSettings{Theme: "dark"}).Value()
+		require.NoError(t, err)
+		require.JSONEq(t, `{"theme": "dark", "tags": null}`, value.(string))
+
+		value, err = JSON[testSettings]{}.Value()
+		require.NoError(t, err)
+		require.Nil(t, value)
+	})
+
+	t.Run("struct scan", func(t *testing.T) {
+		type row struct {
+			ID       int64              `db:"id"`
+			Settings JSON[testSettings] `db:"settings"`
+		}
+
+		got, err := ScanRows[row](queryRows(
+			t,
+			[]string{"id", "settings"},
+			[]any{1, []byte(`{"theme": "dark"}`)},
+			[]any{2, nil},
+		))
+		require.NoError(t, err)
+		require.Equal(t, []row{{ID: 1, Settings: NewJSON(testSettings{Theme: "dark"})}, {ID: 2}}, got)
+	})
+
+	t.Run("api json", func(t *testing.T) {
+		raw, err := json.Marshal(map[string]JSON[testSettings]{"set": NewJSON(testSettings{Theme: "dark"}), "unset": {}})
+		require.NoError(t, err)
+		require.JSONEq(t, `{"set": {"theme": "dark", "tags": null}, "unset": null}`, string(raw))
+
+		var got map[string]JSON[testSettings]
+		require.NoError(t, json.Unmarshal(raw, &got))
+		require.Equal(t, map[string]JSON[testSettings]{"set": NewJSON(testSettings{Theme: "dark"}), "unset": {}}, got)
+	})
+}
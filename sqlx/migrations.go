@@ -0,0 +1,354 @@
+package sqlx
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/jmoiron/sqlx"
+	"github.com/nicjohnson145/hlp"
+)
+
+const (
+	defaultMigrationsTable = "schema_migrations"
+)
+
+var (
+	ErrInvalidMigrationError      = errors.New("invalid migration")
+	ErrChecksumMismatchError      = errors.New("migration checksum mismatch")
+	ErrMissingMigrationError      = errors.New("applied migration missing from source")
+	ErrIrreversibleMigrationError = errors.New("migration has no down script")
+
+	migrationFileRegex = regexp.MustCompile(`^(?P<version>\d+)_(?P<name>.+)\.(?P<direction>up|down)\.sql$`)
+)
+
+// Migration is a single versioned schema change
+type Migration struct {
+	// Version orders migrations, and must be unique
+	Version int64
+	// Name is the descriptive portion of the file name
+	Name string
+	// Up is the SQL applying the migration
+	Up string
+	// Down is the SQL reverting the migration, empty if the migration is irreversible
+	Down string
+	// Checksum is the hex encoded sha256 of Up, used to detect edits to already applied migrations
+	Checksum string
+}
+
+// LoadMigrations reads all migrations from the root of the given filesystem (such as an embed.FS, or the result of
+// fs.Sub). Files must be named `<version>_<name>.up.sql` or `<version>_<name>.down.sql`, and every version requires an
+// up file. Other files are ignored. Migrations are returned ordered by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := hlp.ExtractNamedMatches(migrationFileRegex, migrationFileRegex.FindStringSubmatch(entry.Name()))
+		if len(matches) == 0 {
+			continue
+		}
+
+		version, err := strconv.ParseInt(matches["version"], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v: %w", ErrInvalidMigrationError, entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("error reading %v: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: matches["name"]}
+			byVersion[version] = migration
+		}
+		if migration.Name != matches["name"] {
+			return nil, fmt.Errorf("%w: version %v has conflicting names %v and %v", ErrInvalidMigrationError, version, migration.Name, matches["name"])
+		}
+
+		if matches["direction"] == "up" {
+			migration.Up = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Checksum == "" {
+			return nil, fmt.Errorf("%w: version %v has no up script", ErrInvalidMigrationError, migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+// MigratorOpts are options used to configure the behavior of a Migrator
+type MigratorOpts struct {
+	// Table is the table in which applied migrations are recorded, defaults to "schema_migrations"
+	Table string
+	// Dialect is the dialect of the database, defaults to the result of DialectForDriver
+	Dialect Dialect
+	// Logger is the logger to which to write applied migrations, nil will result in nothing being logged
+	Logger *logr.Logger
+}
+
+// Migrator applies and reverts migrations, recording the applied versions and their checksums in a table. Each
+// migration runs in its own transaction. On Postgres and MySQL, concurrent migrators are serialized through an advisory
+// lock held on a dedicated connection for the duration of the run, so the connection pool must allow at least 2 open
+// connections. Migrations containing multiple statements require a driver that supports them, such as MySQL with
+// `multiStatements=true`
+type Migrator struct {
+	db         *sqlx.DB
+	migrations []Migration
+	table      string
+	dialect    Dialect
+	logger     *logr.Logger
+}
+
+// NewMigrator creates a Migrator for the migrations in the given filesystem, see LoadMigrations for the expected layout
+func NewMigrator(db *sqlx.DB, fsys fs.FS, opts MigratorOpts) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	dialect := opts.Dialect
+	if dialect == "" {
+		dialect, err = DialectForDriver(db.DriverName())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	table := opts.Table
+	if table == "" {
+		table = defaultMigrationsTable
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		table:      table,
+		dialect:    dialect,
+		logger:     opts.Logger,
+	}, nil
+}
+
+type appliedMigration struct {
+	Version  int64  `db:"version"`
+	Name     string `db:"name"`
+	Checksum string `db:"checksum"`
+}
+
+// Up applies all pending migrations in version order, returning the number applied. Before applying anything, it
+// verifies the checksums of already applied migrations, returning ErrChecksumMismatchError if any have been edited
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	return m.locked(ctx, func(applied map[int64]appliedMigration) (int, error) {
+		count := 0
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			err := WithTransactionCtx(ctx, m.db, nil, func(tx *sqlx.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(
+					ctx,
+					tx.Rebind(fmt.Sprintf("INSERT INTO %v (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)", m.quotedTable())),
+					migration.Version,
+					migration.Name,
+					migration.Checksum,
+					time.Now().UTC(),
+				)
+				return err
+			})
+			if err != nil {
+				return count, fmt.Errorf("error applying migration %v_%v: %w", migration.Version, migration.Name, err)
+			}
+			if m.logger != nil {
+				m.logger.Info("applied migration", "version", migration.Version, "name", migration.Name)
+			}
+			count += 1
+		}
+		return count, nil
+	})
+}
+
+// DownTo reverts all applied migrations with a version greater than target in reverse version order, returning the
+// number reverted. A target of 0 reverts every migration
+func (m *Migrator) DownTo(ctx context.Context, target int64) (int, error) {
+	return m.locked(ctx, func(applied map[int64]appliedMigration) (int, error) {
+		count := 0
+		for _, migration := range slices.Backward(m.migrations) {
+			if migration.Version <= target {
+				break
+			}
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return count, fmt.Errorf("%w: %v_%v", ErrIrreversibleMigrationError, migration.Version, migration.Name)
+			}
+
+			err := WithTransactionCtx(ctx, m.db, nil, func(tx *sqlx.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(
+					ctx,
+					tx.Rebind(fmt.Sprintf("DELETE FROM %v WHERE version = ?", m.quotedTable())),
+					migration.Version,
+				)
+				return err
+			})
+			if err != nil {
+				return count, fmt.Errorf("error reverting migration %v_%v: %w", migration.Version, migration.Name, err)
+			}
+			if m.logger != nil {
+				m.logger.Info("reverted migration", "version", migration.Version, "name", migration.Name)
+			}
+			count += 1
+		}
+		return count, nil
+	})
+}
+
+// Version returns the highest applied migration version, or 0 if none have been applied
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return hlp.Max(hlp.Keys(applied)), nil
+}
+
+// locked runs the given function while holding the migration lock, passing it the verified set of applied migrations
+func (m *Migrator) locked(ctx context.Context, workFunc func(applied map[int64]appliedMigration) (int, error)) (int, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	if err := m.ensureTable(ctx); err != nil {
+		return 0, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := m.verify(applied); err != nil {
+		return 0, err
+	}
+
+	return workFunc(applied)
+}
+
+func (m *Migrator) verify(applied map[int64]appliedMigration) error {
+	known := hlp.MapFromSlice(m.migrations, func(migration Migration, _ int) (int64, Migration) {
+		return migration.Version, migration
+	})
+
+	versions := hlp.Keys(applied)
+	slices.Sort(versions)
+	for _, version := range versions {
+		migration, ok := known[version]
+		if !ok {
+			return fmt.Errorf("%w: version %v", ErrMissingMigrationError, version)
+		}
+		if migration.Checksum != applied[version].Checksum {
+			return fmt.Errorf("%w: version %v", ErrChecksumMismatchError, version)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int64]appliedMigration, error) {
+	rows, err := SelectCtx[appliedMigration](ctx, m.db, fmt.Sprintf("SELECT version, name, checksum FROM %v", m.quotedTable()))
+	if err != nil {
+		return nil, fmt.Errorf("error reading applied migrations: %w", err)
+	}
+
+	return hlp.MapFromSlice(rows, func(row appliedMigration, _ int) (int64, appliedMigration) {
+		return row.Version, row
+	}), nil
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS %v (version BIGINT PRIMARY KEY, name VARCHAR(255) NOT NULL, checksum VARCHAR(64) NOT NULL, applied_at TIMESTAMP NOT NULL)",
+		m.quotedTable(),
+	))
+	if err != nil {
+		return fmt.Errorf("error creating migrations table: %w", err)
+	}
+	return nil
+}
+
+// lock acquires the advisory lock for the dialect, if it supports one, returning the function to release it
+func (m *Migrator) lock(ctx context.Context) (func(), error) {
+	var lockQuery, unlockQuery string
+	switch m.dialect {
+	case DialectPostgres:
+		lockQuery, unlockQuery = "SELECT pg_advisory_lock($1)", "SELECT pg_advisory_unlock($1)"
+	case DialectMySQL:
+		lockQuery, unlockQuery = "SELECT GET_LOCK(?, -1)", "SELECT RELEASE_LOCK(?)"
+	default:
+		return func() {}, nil
+	}
+
+	hasher := fnv.New64a()
+	_, _ = hasher.Write([]byte("hlp-migrations:" + m.table))
+	key := int64(hasher.Sum64())
+
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring lock connection: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, lockQuery, key); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("error acquiring migration lock: %w", err)
+	}
+
+	return func() {
+		_, _ = conn.ExecContext(context.Background(), unlockQuery, key)
+		_ = conn.Close()
+	}, nil
+}
+
+func (m *Migrator) quotedTable() string {
+	return quoteIdent(m.dialect, m.table)
+}
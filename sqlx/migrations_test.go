@@ -0,0 +1,166 @@
+package sqlx
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func sqliteDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+
+	db, err := sqlx.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	return db
+}
+
+func testMigrations() fstest.MapFS {
+	return fstest.MapFS{
+		"0001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);")},
+		"0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"0002_add_email.up.sql":      {Data: []byte("ALTER TABLE users ADD COLUMN email TEXT;\nCREATE INDEX users_email ON users (email);")},
+		"0002_add_email.down.sql":    {Data: []byte("DROP INDEX users_email;\nALTER TABLE users DROP COLUMN email;")},
+		"0010_seed.up.sql":           {Data: []byte("INSERT INTO users (id, name) VALUES (1, 'alice');")},
+		"README.md":                  {Data: []byte("not a migration")},
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	t.Run("ordered", func(t *testing.T) {
+		got, err := LoadMigrations(testMigrations())
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+		require.Equal(t, []int64{1, 2, 10}, []int64{got[0].Version, got[1].Version, got[2].Version})
+		require.Equal(t, "create_users", got[0].Name)
+		require.Equal(t, "DROP TABLE users;", got[0].Down)
+		require.Empty(t, got[2].Down)
+		require.Len(t, got[0].Checksum, 64)
+	})
+
+	t.Run("missing up", func(t *testing.T) {
+		_, err := LoadMigrations(fstest.MapFS{
+			"0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		})
+		require.ErrorIs(t, err, ErrInvalidMigrationError)
+	})
+}
+
+func TestMigrator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("up and down", func(t *testing.T) {
+		db := sqliteDB(t)
+		migrator, err := NewMigrator(db, testMigrations(), MigratorOpts{})
+		require.NoError(t, err)
+
+		version, err := migrator.Version(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), version)
+
+		count, err := migrator.Up(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+
+		names, err := SelectCtx[string](ctx, db, "SELECT name FROM users")
+		require.NoError(t, err)
+		require.Equal(t, []string{"alice"}, names)
+
+		count, err = migrator.Up(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 0, count)
+
+		_, err = migrator.DownTo(ctx, 0)
+		require.ErrorIs(t, err, ErrIrreversibleMigrationError)
+
+		version, err = migrator.Version(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(10), version)
+	})
+
+	t.Run("down to target", func(t *testing.T) {
+		db := sqliteDB(t)
+		migrations := testMigrations()
+		delete(migrations, "0010_seed.up.sql")
+		migrator, err := NewMigrator(db, migrations, MigratorOpts{Table: "migrations"})
+		require.NoError(t, err)
+
+		_, err = migrator.Up(ctx)
+		require.NoError(t, err)
+
+		count, err := migrator.DownTo(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		version, err := migrator.Version(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), version)
+
+		_, err = db.ExecContext(ctx, "INSERT INTO users (id, name, email) VALUES (1, 'alice', 'a@example.com')")
+		require.Error(t, err)
+
+		count, err = migrator.DownTo(ctx, 0)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		_, err = db.ExecContext(ctx, "SELECT * FROM users")
+		require.Error(t, err)
+	})
+
+	t.Run("failed migration rolls back", func(t *testing.T) {
+		db := sqliteDB(t)
+		migrations := testMigrations()
+		migrations["0003_broken.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE broken (id INTEGER); SELECT * FROM nope;")}
+		migrator, err := NewMigrator(db, migrations, MigratorOpts{})
+		require.NoError(t, err)
+
+		count, err := migrator.Up(ctx)
+		require.Error(t, err)
+		require.Equal(t, 2, count)
+
+		version, err := migrator.Version(ctx)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), version)
+
+		_, err = db.ExecContext(ctx, "SELECT * FROM broken")
+		require.Error(t, err)
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		db := sqliteDB(t)
+		migrator, err := NewMigrator(db, testMigrations(), MigratorOpts{})
+		require.NoError(t, err)
+		_, err = migrator.Up(ctx)
+		require.NoError(t, err)
+
+		edited := testMigrations()
+		edited["0001_create_users.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE users (id INTEGER PRIMARY KEY);")}
+		migrator, err = NewMigrator(db, edited, MigratorOpts{})
+		require.NoError(t, err)
+
+		_, err = migrator.Up(ctx)
+		require.ErrorIs(t, err, ErrChecksumMismatchError)
+	})
+
+	t.Run("missing migration", func(t *testing.T) {
+		db := sqliteDB(t)
+		migrator, err := NewMigrator(db, testMigrations(), MigratorOpts{})
+		require.NoError(t, err)
+		_, err = migrator.Up(ctx)
+		require.NoError(t, err)
+
+		removed := testMigrations()
+		delete(removed, "0010_seed.up.sql")
+		migrator, err = NewMigrator(db, removed, MigratorOpts{})
+		require.NoError(t, err)
+
+		_, err = migrator.Up(ctx)
+		require.ErrorIs(t, err, ErrMissingMigrationError)
+	})
+}
@@ -0,0 +1,97 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrUnsupportedTransactionTargetError = errors.New("unsupported transaction target")
+
+	// savepointCounter numbers savepoints uniquely across the process, so that a raw *sqlx.Tx re-entering
+	// WithNestedTransactionReturning can never reuse the name of a savepoint that is still open. MySQL silently
+	// replaces a savepoint with the same name, which would break the release of the outer one
+	savepointCounter atomic.Uint64
+)
+
+// NestedTx is a transaction handle that supports nesting through savepoints. It embeds the underlying *sqlx.Tx, so it
+// can be used anywhere a sqlx.ExtContext is expected, and can be passed back into WithNestedTransactionReturning to
+// create a further nested savepoint
+type NestedTx struct {
+	*sqlx.Tx
+
+	depth int
+}
+
+// Depth returns how many savepoints deep the handle is, with 0 being the outermost transaction. Depth is counted from
+// the handle passed to WithNestedTransactionReturning, so a raw *sqlx.Tx always starts again from 0
+func (n *NestedTx) Depth() int {
+	return n.depth
+}
+
+// WithNestedTransaction is like WithNestedTransactionReturning except does not return a value
+func WithNestedTransaction(ctx context.Context, db sqlx.ExtContext, workFunc func(*NestedTx) error) error {
+	_, err := WithNestedTransactionReturning(ctx, db, func(tx *NestedTx) (bool, error) {
+		return false, workFunc(tx)
+	})
+	return err
+}
+
+// WithNestedTransactionReturning executes the provided function within a transaction. If db is a *sqlx.DB a new
+// transaction is opened, exactly like WithTransactionReturningCtx. If db is an already open *sqlx.Tx or *NestedTx, a
+// savepoint is created instead, which is rolled back to if the work function returns error and released otherwise
+func WithNestedTransactionReturning[T any](ctx context.Context, db sqlx.ExtContext, workFunc func(tx *NestedTx) (T, error)) (T, error) {
+	switch typed := db.(type) {
+	case *sqlx.DB:
+		return WithTransactionReturningCtx(ctx, typed, nil, func(tx *sqlx.Tx) (T, error) {
+			return workFunc(&NestedTx{Tx: tx})
+		})
+	case *sqlx.Tx:
+		return withSavepoint(ctx, &NestedTx{Tx: typed}, workFunc)
+	case *NestedTx:
+		return withSavepoint(ctx, typed, workFunc)
+	default:
+		var empty T
+		return empty, fmt.Errorf("%w: %T", ErrUnsupportedTransactionTargetError, db)
+	}
+}
+
+func withSavepoint[T any](ctx context.Context, parent *NestedTx, workFunc func(tx *NestedTx) (T, error)) (T, error) {
+	var empty T
+
+	name := fmt.Sprintf("hlp_savepoint_%v", savepointCounter.Add(1))
+
+	if _, err := parent.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return empty, fmt.Errorf("error creating savepoint: %w", err)
+	}
+
+	child := &NestedTx{
+		Tx:    parent.Tx,
+		depth: parent.depth + 1,
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_, _ = parent.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+			panic(p)
+		}
+	}()
+
+	out, workErr := workFunc(child)
+	if workErr != nil {
+		if _, err := parent.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); err != nil {
+			return empty, fmt.Errorf("error rolling back to savepoint: %w, rollback caused by: %w", err, workErr)
+		}
+		return empty, workErr
+	}
+
+	if _, err := parent.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return empty, fmt.Errorf("error releasing savepoint: %w", err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,163 @@
+package sqlx
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nicjohnson145/hlp/testhlp"
+	"github.com/stretchr/testify/require"
+)
+
+var savepointRegex = regexp.MustCompile(`hlp_savepoint_\d+`)
+
+// savepointEvents renumbers savepoints in the order they first appear, as the real numbering is shared across the
+// whole process
+func savepointEvents(fake *testhlp.FakeDB) []string {
+	seen := map[string]string{}
+	out := []string{}
+	for _, event := range fake.Calls() {
+		out = append(out, savepointRegex.ReplaceAllStringFunc(event, func(name string) string {
+			if _, ok := seen[name]; !ok {
+				seen[name] = fmt.Sprintf("hlp_savepoint_%v", len(seen)+1)
+			}
+			return seen[name]
+		}))
+	}
+	return out
+}
+
+// expectSavepoints expects an exec of each of the given savepoint statements, regardless of the savepoint name
+func expectSavepoints(fake *testhlp.FakeDB, statements ...string) {
+	for _, statement := range statements {
+		fake.ExpectExecRegex(`^` + statement + ` hlp_savepoint_\d+$`)
+	}
+}
+
+func TestWithNestedTransactionReturning(t *testing.T) {
+	t.Run("outermost opens a transaction", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin()
+		fake.ExpectCommit()
+
+		err := WithNestedTransaction(context.Background(), db, func(tx *NestedTx) error {
+			require.Equal(t, 0, tx.Depth())
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"begin", "commit"}, savepointEvents(fake))
+	})
+
+	t.Run("nests with savepoints", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin()
+		expectSavepoints(fake, "SAVEPOINT", "SAVEPOINT", "RELEASE SAVEPOINT", "RELEASE SAVEPOINT", "SAVEPOINT", "ROLLBACK TO SAVEPOINT")
+		fake.ExpectCommit()
+
+		got, err := WithNestedTransactionReturning(context.Background(), db, func(outer *NestedTx) (int, error) {
+			err := WithNestedTransaction(context.Background(), outer, func(inner *NestedTx) error {
+				require.Equal(t, 1, inner.Depth())
+				return WithNestedTransaction(context.Background(), inner, func(innermost *NestedTx) error {
+					require.Equal(t, 2, innermost.Depth())
+					return nil
+				})
+			})
+			if err != nil {
+				return 0, err
+			}
+
+			err = WithNestedTransaction(context.Background(), outer, func(inner *NestedTx) error {
+				return errWork
+			})
+			require.ErrorIs(t, err, errWork)
+
+			return 7, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 7, got)
+		require.Equal(
+			t,
+			[]string{
+				"begin",
+				"exec: SAVEPOINT hlp_savepoint_1",
+				"exec: SAVEPOINT hlp_savepoint_2",
+				"exec: RELEASE SAVEPOINT hlp_savepoint_2",
+				"exec: RELEASE SAVEPOINT hlp_savepoint_1",
+				"exec: SAVEPOINT hlp_savepoint_3",
+				"exec: ROLLBACK TO SAVEPOINT hlp_savepoint_3",
+				"commit",
+			},
+			savepointEvents(fake),
+		)
+	})
+
+	t.Run("joins an existing sqlx transaction", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin()
+		expectSavepoints(fake, "SAVEPOINT", "RELEASE SAVEPOINT")
+		fake.ExpectCommit()
+
+		err := WithTransaction(db, func(tx *sqlx.Tx) error {
+			return WithNestedTransaction(context.Background(), tx, func(inner *NestedTx) error {
+				require.Equal(t, 1, inner.Depth())
+				return nil
+			})
+		})
+		require.NoError(t, err)
+		require.Equal(
+			t,
+			[]string{"begin", "exec: SAVEPOINT hlp_savepoint_1", "exec: RELEASE SAVEPOINT hlp_savepoint_1", "commit"},
+			savepointEvents(fake),
+		)
+	})
+
+	t.Run("rolls back savepoint and re-panics", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin()
+		expectSavepoints(fake, "SAVEPOINT", "ROLLBACK TO SAVEPOINT")
+		fake.ExpectRollback()
+
+		require.Panics(t, func() {
+			_ = WithNestedTransaction(context.Background(), db, func(outer *NestedTx) error {
+				return WithNestedTransaction(context.Background(), outer, func(inner *NestedTx) error {
+					panic("boom")
+				})
+			})
+		})
+		require.Equal(
+			t,
+			[]string{"begin", "exec: SAVEPOINT hlp_savepoint_1", "exec: ROLLBACK TO SAVEPOINT hlp_savepoint_1", "rollback"},
+			savepointEvents(fake),
+		)
+	})
+
+	t.Run("nests through the raw sqlx transaction", func(t *testing.T) {
+		db, fake := newFakeDB(t, "mysql")
+		fake.ExpectBegin()
+		expectSavepoints(fake, "SAVEPOINT", "SAVEPOINT", "RELEASE SAVEPOINT", "RELEASE SAVEPOINT")
+		fake.ExpectCommit()
+
+		err := WithNestedTransaction(context.Background(), db, func(outer *NestedTx) error {
+			return WithNestedTransaction(context.Background(), outer, func(inner *NestedTx) error {
+				return WithNestedTransaction(context.Background(), inner.Tx, func(innermost *NestedTx) error {
+					return nil
+				})
+			})
+		})
+		require.NoError(t, err)
+		require.Equal(
+			t,
+			[]string{
+				"begin",
+				"exec: SAVEPOINT hlp_savepoint_1",
+				"exec: SAVEPOINT hlp_savepoint_2",
+				"exec: RELEASE SAVEPOINT hlp_savepoint_2",
+				"exec: RELEASE SAVEPOINT hlp_savepoint_1",
+				"commit",
+			},
+			savepointEvents(fake),
+		)
+	})
+}
@@ -0,0 +1,60 @@
+package sqlx
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+)
+
+// Null is a sql.Null that additionally marshals to and from JSON as either the held value or `null`
+type Null[T any] struct {
+	sql.Null[T]
+}
+
+// NewNull returns a valid Null holding the given value
+func NewNull[T any](x T) Null[T] {
+	return Null[T]{Null: sql.Null[T]{V: x, Valid: true}}
+}
+
+// NullFromPointer returns a Null holding the pointed to value, or NULL if the pointer is nil
+func NullFromPointer[T any](x *T) Null[T] {
+	return Null[T]{Null: PointerToSqlNull(x)}
+}
+
+// Ptr returns a pointer to the held value, or nil if NULL
+func (n Null[T]) Ptr() *T {
+	return SqlNullToPointer(n.Null)
+}
+
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	return marshalNullJSON(n.V, n.Valid)
+}
+
+func (n *Null[T]) UnmarshalJSON(data []byte) error {
+	v, valid, err := unmarshalNullJSON[T](data)
+	if err != nil {
+		return err
+	}
+	*n = Null[T]{Null: sql.Null[T]{V: v, Valid: valid}}
+	return nil
+}
+
+// marshalNullJSON marshals the value, or `null` if not valid
+func marshalNullJSON[T any](v T, valid bool) ([]byte, error) {
+	if !valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(v)
+}
+
+// unmarshalNullJSON is the inverse of marshalNullJSON
+func unmarshalNullJSON[T any](data []byte) (T, bool, error) {
+	var v T
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return v, false, nil
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, false, err
+	}
+	return v, true, nil
+}
@@ -0,0 +1,52 @@
+package sqlx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNull(t *testing.T) {
+	type dto struct {
+		Name  Null[string] `json:"name" db:"name"`
+		Count Null[int64]  `json:"count" db:"count"`
+	}
+
+	t.Run("json round trip", func(t *testing.T) {
+		original := dto{Name: NewNull("alice")}
+
+		raw, err := json.Marshal(original)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"name": "alice", "count": null}`, string(raw))
+
+		var got dto
+		require.NoError(t, json.Unmarshal(raw, &got))
+		require.Equal(t, original, got)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		var got dto
+		require.Error(t, json.Unmarshal([]byte(`{"count": "seven"}`), &got))
+	})
+
+	t.Run("pointers", func(t *testing.T) {
+		x := "a"
+		require.Equal(t, &x, NullFromPointer(&x).Ptr())
+		require.Nil(t, NullFromPointer[string](nil).Ptr())
+	})
+
+	t.Run("database", func(t *testing.T) {
+		value, err := NewNull("alice").Value()
+		require.NoError(t, err)
+		require.Equal(t, "alice", value)
+
+		value, err = Null[string]{}.Value()
+		require.NoError(t, err)
+		require.Nil(t, value)
+
+		got, err := ScanRows[dto](queryRows(t, []string{"name", "count"}, []any{"alice", nil}))
+		require.NoError(t, err)
+		require.Equal(t, []dto{{Name: NewNull("alice")}}, got)
+	})
+}
@@ -0,0 +1,291 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrInvalidCursorError   = errors.New("invalid cursor")
+	ErrInvalidPageOptsError = errors.New("invalid page options")
+
+	pageColumnRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// OrderColumn is a single column of a keyset ordering
+type OrderColumn struct {
+	// Column is the name of a column in the result set of the base query, which must match the `db` tag of a field of
+	// the scanned type
+	Column string
+	// Descending orders the column from highest to lowest
+	Descending bool
+}
+
+// PageOpts are options used to configure the behavior of SelectPageNamedCtx
+type PageOpts struct {
+	// OrderBy are the columns the results are ordered by. Together they must uniquely identify a row (usually by ending
+	// in a primary key), and none may be NULL
+	OrderBy []OrderColumn
+	// Limit is the maximum number of items in the page
+	Limit int
+	// Cursor is either NextCursor or PrevCursor of a previous page, or empty for the first page
+	Cursor string
+}
+
+// Page is a single page of results from SelectPageNamedCtx
+type Page[T any] struct {
+	// Items are the results in the page, in the requested order
+	Items []T
+	// NextCursor retrieves the page after this one, empty if there are no further results
+	NextCursor string
+	// PrevCursor retrieves the page before this one, empty if this is the first page
+	PrevCursor string
+}
+
+type cursorValue struct {
+	Type  string          `json:"t"`
+	Value json.RawMessage `json:"v"`
+}
+
+type cursor struct {
+	Backward  bool          `json:"b,omitempty"`
+	Inclusive bool          `json:"i,omitempty"`
+	Keys      []cursorValue `json:"k"`
+}
+
+// SelectPageNamedCtx executes a query with named parameters, returning a single page of results using keyset
+// pagination. Like SelectNamedCtx, args may be a map or a `db` tagged struct. The base query is wrapped in a subquery,
+// so should not include its own ORDER BY or LIMIT. Cursors are URL-safe base64 encodings of the ordering key values of
+// the first or last item in the page
+func SelectPageNamedCtx[T any](ctx context.Context, db sqlx.ExtContext, query string, args any, opts PageOpts) (Page[T], error) {
+	if len(opts.OrderBy) == 0 || opts.Limit <= 0 {
+		return Page[T]{}, fmt.Errorf("%w: at least one order column and a positive limit are required", ErrInvalidPageOptsError)
+	}
+	for _, col := range opts.OrderBy {
+		if !pageColumnRegex.MatchString(col.Column) {
+			return Page[T]{}, fmt.Errorf("%w: invalid column name %q", ErrInvalidPageOptsError, col.Column)
+		}
+	}
+
+	var cur cursor
+	var keys []any
+	if opts.Cursor != "" {
+		var err error
+		cur, keys, err = decodeCursor(opts.Cursor, len(opts.OrderBy))
+		if err != nil {
+			return Page[T]{}, err
+		}
+	}
+
+	// The base query is bound to `?` placeholders, so the cursor parameters can simply be appended after its own
+	params := []any{}
+	if args != nil {
+		var err error
+		query, params, err = sqlx.Named(query, args)
+		if err != nil {
+			return Page[T]{}, fmt.Errorf("error binding query: %w", err)
+		}
+	}
+
+	where := ""
+	if keys != nil {
+		ors := []string{}
+		for i, col := range opts.OrderBy {
+			ands := []string{}
+			for j := 0; j < i; j++ {
+				ands = append(ands, fmt.Sprintf("%v = ?", opts.OrderBy[j].Column))
+				params = append(params, keys[j])
+			}
+			op := ">"
+			if col.Descending != cur.Backward {
+				op = "<"
+			}
+			if cur.Inclusive && i == len(opts.OrderBy)-1 {
+				op += "="
+			}
+			ands = append(ands, fmt.Sprintf("%v %v ?", col.Column, op))
+			params = append(params, keys[i])
+			ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+		}
+		where = " WHERE " + strings.Join(ors, " OR ")
+	}
+
+	orders := []string{}
+	for _, col := range opts.OrderBy {
+		dir := "ASC"
+		if col.Descending != cur.Backward {
+			dir = "DESC"
+		}
+		orders = append(orders, col.Column+" "+dir)
+	}
+
+	pageQuery := fmt.Sprintf(
+		"SELECT * FROM (%v) AS hlp_page%v ORDER BY %v LIMIT %v",
+		query,
+		where,
+		strings.Join(orders, ", "),
+		opts.Limit+1,
+	)
+
+	rows, err := SelectCtx[T](ctx, db, pageQuery, params...)
+	if err != nil {
+		return Page[T]{}, err
+	}
+
+	hasMore := len(rows) > opts.Limit
+	if hasMore {
+		rows = rows[:opts.Limit]
+	}
+	if cur.Backward {
+		slices.Reverse(rows)
+	}
+
+	page := Page[T]{Items: rows}
+	if len(rows) == 0 {
+		// Rows may have been deleted since the cursor was issued. Rather than stranding the client, offer a cursor back
+		// in the other direction that includes the row the cursor was taken from
+		if opts.Cursor != "" {
+			turned := cursor{Backward: !cur.Backward, Inclusive: true, Keys: cur.Keys}
+			encoded, err := turned.encode()
+			if err != nil {
+				return Page[T]{}, err
+			}
+			if cur.Backward {
+				page.NextCursor = encoded
+			} else {
+				page.PrevCursor = encoded
+			}
+		}
+		return page, nil
+	}
+
+	// Going forward, there is a previous page if we started from a cursor and a next page if there were more rows.
+	// Going backward, the reverse is true
+	hasNext, hasPrev := hasMore, opts.Cursor != ""
+	if cur.Backward {
+		hasNext, hasPrev = true, hasMore
+	}
+	if hasNext {
+		page.NextCursor, err = encodeCursor(rows[len(rows)-1], opts.OrderBy, false)
+		if err != nil {
+			return Page[T]{}, err
+		}
+	}
+	if hasPrev {
+		page.PrevCursor, err = encodeCursor(rows[0], opts.OrderBy, true)
+		if err != nil {
+			return Page[T]{}, err
+		}
+	}
+
+	return page, nil
+}
+
+func encodeCursor[T any](item T, orderBy []OrderColumn, backward bool) (string, error) {
+	value := reflect.Indirect(reflect.ValueOf(item))
+	if value.Kind() != reflect.Struct {
+		return "", fmt.Errorf("%w: %T is not a struct", ErrInvalidPageOptsError, item)
+	}
+
+	cur := cursor{Backward: backward}
+	for _, col := range orderBy {
+		field := columnMapper.FieldByName(value, col.Column)
+		if !field.IsValid() {
+			return "", fmt.Errorf("%w: %T has no field for column %v", ErrInvalidPageOptsError, item, col.Column)
+		}
+
+		converted, err := driver.DefaultParameterConverter.ConvertValue(field.Interface())
+		if err != nil {
+			return "", fmt.Errorf("error converting %v: %w", col.Column, err)
+		}
+
+		var typ string
+		switch converted.(type) {
+		case int64:
+			typ = "int"
+		case float64:
+			typ = "float"
+		case bool:
+			typ = "bool"
+		case []byte:
+			typ = "bytes"
+		case string:
+			typ = "string"
+		case time.Time:
+			typ = "time"
+		default:
+			return "", fmt.Errorf("%w: column %v has unsupported value %v", ErrInvalidPageOptsError, col.Column, converted)
+		}
+
+		raw, err := json.Marshal(converted)
+		if err != nil {
+			return "", fmt.Errorf("error encoding %v: %w", col.Column, err)
+		}
+		cur.Keys = append(cur.Keys, cursorValue{Type: typ, Value: raw})
+	}
+
+	return cur.encode()
+}
+
+func (c cursor) encode() (string, error) {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("error encoding cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(encoded string, keyCount int) (cursor, []any, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor{}, nil, fmt.Errorf("%w: %w", ErrInvalidCursorError, err)
+	}
+
+	var cur cursor
+	if err := json.Unmarshal(raw, &cur); err != nil {
+		return cursor{}, nil, fmt.Errorf("%w: %w", ErrInvalidCursorError, err)
+	}
+	if len(cur.Keys) != keyCount {
+		return cursor{}, nil, fmt.Errorf("%w: expected %v keys, got %v", ErrInvalidCursorError, keyCount, len(cur.Keys))
+	}
+
+	keys := make([]any, len(cur.Keys))
+	for i, key := range cur.Keys {
+		var dest any
+		switch key.Type {
+		case "int":
+			dest = new(int64)
+		case "float":
+			dest = new(float64)
+		case "bool":
+			dest = new(bool)
+		case "bytes":
+			dest = new([]byte)
+		case "string":
+			dest = new(string)
+		case "time":
+			dest = new(time.Time)
+		default:
+			return cursor{}, nil, fmt.Errorf("%w: unknown key type %q", ErrInvalidCursorError, key.Type)
+		}
+
+		if err := json.Unmarshal(key.Value, dest); err != nil {
+			return cursor{}, nil, fmt.Errorf("%w: %w", ErrInvalidCursorError, err)
+		}
+		keys[i] = reflect.ValueOf(dest).Elem().Interface()
+	}
+
+	return cur, keys, nil
+}
@@ -0,0 +1,117 @@
+package sqlx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testScore struct {
+	ID    int64  `db:"id"`
+	Name  string `db:"name"`
+	Score int64  `db:"score"`
+}
+
+func TestSelectPageNamedCtx(t *testing.T) {
+	ctx := context.Background()
+
+	db := sqliteDB(t)
+	_, err := db.ExecContext(ctx, "CREATE TABLE scores (id INTEGER PRIMARY KEY, name TEXT NOT NULL, score INTEGER NOT NULL)")
+	require.NoError(t, err)
+	_, err = BulkInsertNamedCtx(ctx, db, "scores", []testScore{
+		{ID: 1, Name: "a", Score: 10},
+		{ID: 2, Name: "b", Score: 30},
+		{ID: 3, Name: "c", Score: 20},
+		{ID: 4, Name: "d", Score: 30},
+		{ID: 5, Name: "e", Score: 10},
+		{ID: 6, Name: "f", Score: 50},
+		{ID: 7, Name: "x", Score: 99},
+	}, 999)
+	require.NoError(t, err)
+
+	query := "SELECT id, name, score FROM scores WHERE name != :excluded"
+	args := map[string]any{"excluded": "x"}
+	opts := PageOpts{
+		OrderBy: []OrderColumn{{Column: "score", Descending: true}, {Column: "id"}},
+		Limit:   2,
+	}
+	ids := func(page Page[testScore]) []int64 {
+		out := []int64{}
+		for _, item := range page.Items {
+			out = append(out, item.ID)
+		}
+		return out
+	}
+	fetch := func(cursor string) Page[testScore] {
+		opts := opts
+		opts.Cursor = cursor
+		page, err := SelectPageNamedCtx[testScore](ctx, db, query, args, opts)
+		require.NoError(t, err)
+		return page
+	}
+
+	first := fetch("")
+	require.Equal(t, []int64{6, 2}, ids(first))
+	require.Empty(t, first.PrevCursor)
+	require.NotEmpty(t, first.NextCursor)
+
+	second := fetch(first.NextCursor)
+	require.Equal(t, []int64{4, 3}, ids(second))
+	require.NotEmpty(t, second.PrevCursor)
+
+	third := fetch(second.NextCursor)
+	require.Equal(t, []int64{1, 5}, ids(third))
+	require.Empty(t, third.NextCursor)
+
+	backToSecond := fetch(third.PrevCursor)
+	require.Equal(t, []int64{4, 3}, ids(backToSecond))
+	require.NotEmpty(t, backToSecond.NextCursor)
+
+	backToFirst := fetch(backToSecond.PrevCursor)
+	require.Equal(t, []int64{6, 2}, ids(backToFirst))
+	require.Empty(t, backToFirst.PrevCursor)
+	require.Equal(t, first.NextCursor, backToFirst.NextCursor)
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		opts := opts
+		opts.Cursor = "not a cursor!"
+		_, err := SelectPageNamedCtx[testScore](ctx, db, query, args, opts)
+		require.ErrorIs(t, err, ErrInvalidCursorError)
+	})
+
+	t.Run("invalid column", func(t *testing.T) {
+		opts := opts
+		opts.OrderBy = []OrderColumn{{Column: "id; DROP TABLE scores"}}
+		_, err := SelectPageNamedCtx[testScore](ctx, db, query, args, opts)
+		require.ErrorIs(t, err, ErrInvalidPageOptsError)
+	})
+
+	t.Run("struct args", func(t *testing.T) {
+		page, err := SelectPageNamedCtx[testScore](ctx, db, "SELECT id, name, score FROM scores WHERE score < :score", testScore{Score: 30}, opts)
+		require.NoError(t, err)
+		require.Equal(t, []int64{3, 1}, ids(page))
+
+		page, err = SelectPageNamedCtx[testScore](ctx, db, "SELECT id, name, score FROM scores WHERE score < :score", testScore{Score: 30}, PageOpts{
+			OrderBy: opts.OrderBy,
+			Limit:   opts.Limit,
+			Cursor:  page.NextCursor,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int64{5}, ids(page))
+	})
+
+	t.Run("recovers from an empty backward page", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, "DELETE FROM scores WHERE id IN (2, 3, 4, 6)")
+		require.NoError(t, err)
+
+		empty := fetch(third.PrevCursor)
+		require.Empty(t, empty.Items)
+		require.Empty(t, empty.PrevCursor)
+		require.NotEmpty(t, empty.NextCursor)
+
+		recovered := fetch(empty.NextCursor)
+		require.Equal(t, []int64{1, 5}, ids(recovered))
+		require.Empty(t, recovered.NextCursor)
+	})
+}
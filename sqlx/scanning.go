@@ -1,13 +1,19 @@
 package sqlx
 
 import (
+	"database/sql"
 	"fmt"
+	"iter"
+	"reflect"
 
 	"github.com/jmoiron/sqlx"
 )
 
-// ScanRows scans each row object into the indicated struct, returning a list of all objects. It delegates the closing
-// of the rows object to the caller
+var scannerType = reflect.TypeFor[sql.Scanner]()
+
+// ScanRows scans each row object into the indicated type, returning a list of all objects. Structs are scanned by
+// column name, while non-struct types and types implementing sql.Scanner are scanned directly from a single column
+// query. It delegates the closing of the rows object to the caller
 func ScanRows[T any](rows *sqlx.Rows) ([]T, error) {
 	outRows := make([]T, 0)
 
@@ -22,12 +28,13 @@ func ScanRows[T any](rows *sqlx.Rows) ([]T, error) {
 	return outRows, nil
 }
 
-// IScanRows scans each row into the indicated struct, and calls the process function on it, stopping on the first
-// error. It delegates the closing of the rows object to the caller
+// IScanRows scans each row into the indicated type, and calls the process function on it, stopping on the first
+// error. See ScanRows for how rows are scanned. It delegates the closing of the rows object to the caller
 func IScanRows[T any](rows *sqlx.Rows, processFunc func(T) error) error {
+	scan := rowScanner[T]()
 	for rows.Next() {
-		var out T
-		if err := rows.StructScan(&out); err != nil {
+		out, err := scan(rows)
+		if err != nil {
 			return fmt.Errorf("error scanning: %w", err)
 		}
 
@@ -41,3 +48,131 @@ func IScanRows[T any](rows *sqlx.Rows, processFunc func(T) error) error {
 
 	return nil
 }
+
+// ScanRowsSeq returns an iterator that lazily scans each row into the indicated type, see ScanRows for how rows are
+// scanned. Scanning or iteration errors are yielded alongside a zero value, after which iteration ends. Unlike
+// ScanRows, the rows object is closed once iteration finishes or is stopped early
+func ScanRowsSeq[T any](rows *sqlx.Rows) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer rows.Close()
+
+		scan := rowScanner[T]()
+		for rows.Next() {
+			out, err := scan(rows)
+			if err != nil {
+				var empty T
+				yield(empty, fmt.Errorf("error scanning: %w", err))
+				return
+			}
+
+			if !yield(out, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			var empty T
+			yield(empty, err)
+		}
+	}
+}
+
+// rowScanner returns the function used to scan a single row into a T, scanning directly for scalar types and by column
+// name for structs
+func rowScanner[T any]() func(*sqlx.Rows) (T, error) {
+	scan := func(rows *sqlx.Rows) (T, error) {
+		var out T
+		err := rows.StructScan(&out)
+		return out, err
+	}
+	if isScalar(reflect.TypeFor[T]()) {
+		scan = func(rows *sqlx.Rows) (T, error) {
+			var out T
+			err := rows.Scan(&out)
+			return out, err
+		}
+	}
+	return scan
+}
+
+// isScalar reports if the type should be scanned directly from a single column rather than mapped by column name. This
+// is the case for sql.Scanner implementations, non-structs, and structs with no mapped fields such as time.Time
+func isScalar(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(scannerType) {
+		return true
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+	return len(columnMapper.TypeMap(t).Index) == 0
+}
+
+// ScanRowsToMap scans each row into the indicated type, returning a map of the results keyed by the return value of
+// keyFunc. Later rows replace earlier rows with the same key. It delegates the closing of the rows object to the caller
+func ScanRowsToMap[K comparable, T any](rows *sqlx.Rows, keyFunc func(T) K) (map[K]T, error) {
+	out := map[K]T{}
+
+	err := IScanRows(rows, func(item T) error {
+		out[keyFunc(item)] = item
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error iterating: %w", err)
+	}
+
+	return out, nil
+}
+
+// ScanRowsGrouped scans each row into the indicated type, returning the results grouped by the return value of
+// keyFunc, preserving row order within each group. It delegates the closing of the rows object to the caller
+func ScanRowsGrouped[K comparable, T any](rows *sqlx.Rows, keyFunc func(T) K) (map[K][]T, error) {
+	out := map[K][]T{}
+
+	err := IScanRows(rows, func(item T) error {
+		key := keyFunc(item)
+		out[key] = append(out[key], item)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error iterating: %w", err)
+	}
+
+	return out, nil
+}
+
+// ScanRowsOneToMany scans the results of a one-to-many JOIN into parent objects with nested children. Each row is
+// scanned into R, from which split extracts the parent key, the parent, the child, and whether the child is present
+// (false for the NULL side of a LEFT JOIN). The parent from the first row of each key is kept, and attach is called to
+// add each present child to it. Parents are returned in order of first appearance. It delegates the closing of the
+// rows object to the caller
+func ScanRowsOneToMany[R any, K comparable, P any, C any](rows *sqlx.Rows, split func(row R) (K, P, C, bool), attach func(parent *P, child C)) ([]P, error) {
+	parents := []*P{}
+	byKey := map[K]*P{}
+
+	err := IScanRows(rows, func(row R) error {
+		key, parent, child, hasChild := split(row)
+
+		existing, ok := byKey[key]
+		if !ok {
+			existing = &parent
+			byKey[key] = existing
+			parents = append(parents, existing)
+		}
+		if hasChild {
+			attach(existing, child)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error iterating: %w", err)
+	}
+
+	out := make([]P, len(parents))
+	for i, parent := range parents {
+		out[i] = *parent
+	}
+
+	return out, nil
+}
@@ -0,0 +1,232 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/nicjohnson145/hlp/testhlp"
+	"github.com/stretchr/testify/require"
+)
+
+type testUser struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+// newFakeDB opens a *sqlx.DB backed by a testhlp.FakeDB, using the given driver name to decide on bindvar style
+func newFakeDB(t *testing.T, driverName string) (*sqlx.DB, *testhlp.FakeDB) {
+	db, fake := testhlp.NewFakeDB(t)
+	return sqlx.NewDb(db, driverName), fake
+}
+
+// expectUsers expects a query exactly matching the given text, returning three users
+func expectUsers(fake *testhlp.FakeDB, query string) *testhlp.Expectation {
+	return fake.ExpectQuery(query).WillReturnRows(
+		[]string{"id", "name"},
+		[]any{1, "alice"},
+		[]any{2, "bob"},
+		[]any{3, "carol"},
+	)
+}
+
+// queryRows returns the given rows as the result of a query against a fake database
+func queryRows(t *testing.T, columns []string, values ...[]any) *sqlx.Rows {
+	db, fake := newFakeDB(t, "postgres")
+	fake.ExpectQuery("SELECT").WillReturnRows(columns, values...)
+
+	got, err := db.QueryxContext(context.Background(), "SELECT")
+	require.NoError(t, err)
+	return got
+}
+
+// queryUsers returns the three users of expectUsers as the result of a query against a fake database
+func queryUsers(t *testing.T) *sqlx.Rows {
+	return queryRows(t, []string{"id", "name"}, []any{1, "alice"}, []any{2, "bob"}, []any{3, "carol"})
+}
+
+func TestScanRowsSeq(t *testing.T) {
+	t.Run("yields all rows", func(t *testing.T) {
+		got := []testUser{}
+		for user, err := range ScanRowsSeq[testUser](queryUsers(t)) {
+			require.NoError(t, err)
+			got = append(got, user)
+		}
+		require.Equal(t, []testUser{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}, {ID: 3, Name: "carol"}}, got)
+	})
+
+	t.Run("closes rows on early stop", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		expectation := expectUsers(fake, "SELECT id, name FROM users")
+
+		rows, err := db.QueryxContext(context.Background(), "SELECT id, name FROM users")
+		require.NoError(t, err)
+
+		for user, err := range ScanRowsSeq[testUser](rows) {
+			require.NoError(t, err)
+			require.Equal(t, testUser{ID: 1, Name: "alice"}, user)
+			break
+		}
+		require.True(t, expectation.RowsClosed())
+	})
+
+	t.Run("yields zero value on scan error", func(t *testing.T) {
+		type badUser struct {
+			ID   int64 `db:"id"`
+			Name int64 `db:"name"`
+		}
+
+		count := 0
+		for user, err := range ScanRowsSeq[badUser](queryUsers(t)) {
+			count += 1
+			require.Error(t, err)
+			require.Equal(t, badUser{}, user)
+		}
+		require.Equal(t, 1, count)
+	})
+
+	t.Run("surfaces rows error", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		expectUsers(fake, "SELECT id, name FROM users").WillReturnRowError(errWork)
+
+		rows, err := db.QueryxContext(context.Background(), "SELECT id, name FROM users")
+		require.NoError(t, err)
+
+		count := 0
+		var lastErr error
+		for _, err := range ScanRowsSeq[testUser](rows) {
+			count += 1
+			lastErr = err
+		}
+		require.Equal(t, 4, count)
+		require.ErrorIs(t, lastErr, errWork)
+	})
+}
+
+func TestSelectNamedSeqCtx(t *testing.T) {
+	t.Run("query error", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectQuery("SELECT id, name FROM users WHERE id = $1").WillReturnError(errWork)
+
+		for _, err := range SelectNamedSeqCtx[testUser](context.Background(), db, "SELECT id, name FROM users WHERE id = :id", map[string]any{"id": 1}) {
+			require.ErrorIs(t, err, errWork)
+		}
+	})
+
+	t.Run("streams rows", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		expectUsers(fake, "SELECT id, name FROM users WHERE id > $1").WithArgs(1)
+
+		names := []string{}
+		for user, err := range SelectNamedSeqCtx[testUser](context.Background(), db, "SELECT id, name FROM users WHERE id > :id", map[string]any{"id": 1}) {
+			require.NoError(t, err)
+			names = append(names, user.Name)
+		}
+		require.Equal(t, []string{"alice", "bob", "carol"}, names)
+	})
+}
+
+func TestScanRows(t *testing.T) {
+	t.Run("structs", func(t *testing.T) {
+		got, err := ScanRows[testUser](queryUsers(t))
+		require.NoError(t, err)
+		require.Equal(t, []testUser{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}, {ID: 3, Name: "carol"}}, got)
+	})
+
+	t.Run("embedded unexported structs", func(t *testing.T) {
+		type adminUser struct {
+			testUser
+		}
+
+		got, err := ScanRows[adminUser](queryUsers(t))
+		require.NoError(t, err)
+		require.Equal(t, []adminUser{{testUser{ID: 1, Name: "alice"}}, {testUser{ID: 2, Name: "bob"}}, {testUser{ID: 3, Name: "carol"}}}, got)
+	})
+
+	t.Run("primitives", func(t *testing.T) {
+		got, err := ScanRows[int64](queryRows(t, []string{"id"}, []any{1}, []any{2}))
+		require.NoError(t, err)
+		require.Equal(t, []int64{1, 2}, got)
+	})
+
+	t.Run("scanners", func(t *testing.T) {
+		got, err := ScanRows[sql.NullString](queryRows(t, []string{"name"}, []any{"alice"}, []any{nil}))
+		require.NoError(t, err)
+		require.Equal(t, []sql.NullString{{String: "alice", Valid: true}, {}}, got)
+	})
+
+	t.Run("opaque structs", func(t *testing.T) {
+		now := time.Now().UTC()
+		got, err := ScanRows[time.Time](queryRows(t, []string{"created_at"}, []any{now}))
+		require.NoError(t, err)
+		require.Equal(t, []time.Time{now}, got)
+	})
+}
+
+func TestScanRowsToMap(t *testing.T) {
+	rows := queryUsers(t)
+
+	got, err := ScanRowsToMap(rows, func(u testUser) int64 { return u.ID })
+	require.NoError(t, err)
+	require.Equal(t, map[int64]testUser{1: {ID: 1, Name: "alice"}, 2: {ID: 2, Name: "bob"}, 3: {ID: 3, Name: "carol"}}, got)
+}
+
+func TestScanRowsGrouped(t *testing.T) {
+	rows := queryUsers(t)
+
+	got, err := ScanRowsGrouped(rows, func(u testUser) bool { return u.ID%2 == 0 })
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		map[bool][]testUser{
+			true:  {{ID: 2, Name: "bob"}},
+			false: {{ID: 1, Name: "alice"}, {ID: 3, Name: "carol"}},
+		},
+		got,
+	)
+}
+
+func TestScanRowsOneToMany(t *testing.T) {
+	type order struct {
+		ID    int64 `db:"order_id"`
+		Total int64 `db:"total"`
+	}
+	type userWithOrders struct {
+		testUser
+		Orders []order
+	}
+	type joinRow struct {
+		testUser
+		OrderID sql.NullInt64 `db:"order_id"`
+		Total   sql.NullInt64 `db:"total"`
+	}
+
+	rows := queryRows(
+		t,
+		[]string{"id", "name", "order_id", "total"},
+		[]any{1, "alice", 10, 100},
+		[]any{2, "bob", nil, nil},
+		[]any{1, "alice", 11, 200},
+	)
+
+	got, err := ScanRowsOneToMany(
+		rows,
+		func(row joinRow) (int64, userWithOrders, order, bool) {
+			return row.ID, userWithOrders{testUser: row.testUser}, order{ID: row.OrderID.Int64, Total: row.Total.Int64}, row.OrderID.Valid
+		},
+		func(parent *userWithOrders, child order) {
+			parent.Orders = append(parent.Orders, child)
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(
+		t,
+		[]userWithOrders{
+			{testUser: testUser{ID: 1, Name: "alice"}, Orders: []order{{ID: 10, Total: 100}, {ID: 11, Total: 200}}},
+			{testUser: testUser{ID: 2, Name: "bob"}},
+		},
+		got,
+	)
+}
@@ -2,8 +2,10 @@ package sqlx
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"iter"
 
 	"github.com/jmoiron/sqlx"
 )
@@ -15,13 +17,48 @@ var (
 
 // SelectNamedCtx executes a query with named paramters, and scans the results into the supplied struct
 func SelectNamedCtx[T any](ctx context.Context, db sqlx.ExtContext, query string, args any) ([]T, error) {
+	ctx, tracker := trackRows(ctx)
 	rows, err := sqlx.NamedQueryContext(ctx, db, query, args)
 	if err != nil {
 		return nil, fmt.Errorf("error querying: %w", err)
 	}
 	defer rows.Close()
 
-	return ScanRows[T](rows)
+	out, err := ScanRows[T](rows)
+	tracker.done(int64(len(out)), err)
+	return out, err
+}
+
+// SelectNamedSeqCtx is like SelectNamedCtx, but returns an iterator that streams the scanned results rather than
+// loading them all into memory. The query is executed when iteration begins, and any error executing it is yielded as
+// the first and only element
+func SelectNamedSeqCtx[T any](ctx context.Context, db sqlx.ExtContext, query string, args any) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		ctx, tracker := trackRows(ctx)
+		rows, err := sqlx.NamedQueryContext(ctx, db, query, args)
+		if err != nil {
+			var empty T
+			yield(empty, fmt.Errorf("error querying: %w", err))
+			return
+		}
+
+		var count int64
+		var scanErr error
+		defer func() {
+			tracker.done(count, scanErr)
+		}()
+
+		for out, err := range ScanRowsSeq[T](rows) {
+			if err != nil {
+				scanErr = err
+			} else {
+				count += 1
+			}
+			if !yield(out, err) {
+				return
+			}
+		}
+	}
 }
 
 // RequireExactSelectNamedCtx is like SelectNamedCtx, except it enforces that the number of rows returned matches an
@@ -32,23 +69,117 @@ func RequireExactSelectNamedCtx[T any](ctx context.Context, expected int, db sql
 		return nil, err
 	}
 
-	if count := len(rows); count != expected {
-		if expected > 0 && count == 0 {
-			return nil, ErrNotFoundError
-		}
-		return nil, fmt.Errorf("%w: expected %v, got %v", ErrUnexpectedRowCountError, expected, count)
+	if err := checkCount(expected, int64(len(rows))); err != nil {
+		return nil, err
 	}
 
 	return rows, nil
 }
 
-// SelectCtx is exactly like SelectNamedCtx, but for quries that require no arguments
-func SelectCtx[T any](ctx context.Context, db sqlx.ExtContext, query string) ([]T, error) {
-	rows, err := db.QueryxContext(ctx, query)
+// SelectCtx is exactly like SelectNamedCtx, but for queries that take positional arguments, or no arguments at all.
+// When arguments are given, `?` placeholders in the query are rebound to the bindvar style of the driver
+func SelectCtx[T any](ctx context.Context, db sqlx.ExtContext, query string, args ...any) ([]T, error) {
+	ctx, tracker := trackRows(ctx)
+	rows, err := db.QueryxContext(ctx, rebind(db, query, args), args...)
 	if err != nil {
 		return nil, fmt.Errorf("error querying: %w", err)
 	}
 	defer rows.Close()
 
-	return ScanRows[T](rows)
+	out, err := ScanRows[T](rows)
+	tracker.done(int64(len(out)), err)
+	return out, err
+}
+
+// RequireExactSelectCtx is like SelectCtx, except it enforces that the number of rows returned matches an expected
+// value
+func RequireExactSelectCtx[T any](ctx context.Context, expected int, db sqlx.ExtContext, query string, args ...any) ([]T, error) {
+	rows, err := SelectCtx[T](ctx, db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkCount(expected, int64(len(rows))); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// rebind converts the `?` placeholders in query to the bindvar style of the driver, leaving queries without arguments
+// untouched
+func rebind(db sqlx.ExtContext, query string, args []any) string {
+	if len(args) == 0 {
+		return query
+	}
+	return db.Rebind(query)
+}
+
+func checkCount(expected int, count int64) error {
+	if count != int64(expected) {
+		if expected > 0 && count == 0 {
+			return ErrNotFoundError
+		}
+		return fmt.Errorf("%w: expected %v, got %v", ErrUnexpectedRowCountError, expected, count)
+	}
+	return nil
+}
+
+// GetNamedCtx executes a query with named parameters, and scans the single resulting row into the supplied type. If no
+// rows are returned, ErrNotFoundError (which also wraps sql.ErrNoRows) is returned. If more than one row is returned,
+// ErrUnexpectedRowCountError is returned without reading past the second row
+func GetNamedCtx[T any](ctx context.Context, db sqlx.ExtContext, query string, args any) (T, error) {
+	ctx, tracker := trackRows(ctx)
+	rows, err := sqlx.NamedQueryContext(ctx, db, query, args)
+	if err != nil {
+		var empty T
+		return empty, fmt.Errorf("error querying: %w", err)
+	}
+	defer rows.Close()
+
+	out, count, err := scanOne[T](rows)
+	tracker.done(count, err)
+	return out, err
+}
+
+// GetCtx is exactly like GetNamedCtx, but for queries that take positional arguments. Placeholders are rebound in the
+// same manner as SelectCtx
+func GetCtx[T any](ctx context.Context, db sqlx.ExtContext, query string, args ...any) (T, error) {
+	ctx, tracker := trackRows(ctx)
+	rows, err := db.QueryxContext(ctx, rebind(db, query, args), args...)
+	if err != nil {
+		var empty T
+		return empty, fmt.Errorf("error querying: %w", err)
+	}
+	defer rows.Close()
+
+	out, count, err := scanOne[T](rows)
+	tracker.done(count, err)
+	return out, err
+}
+
+// scanOne scans the single row of the result, also returning the number of rows read
+func scanOne[T any](rows *sqlx.Rows) (T, int64, error) {
+	var empty T
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return empty, 0, err
+		}
+		return empty, 0, fmt.Errorf("%w: %w", ErrNotFoundError, sql.ErrNoRows)
+	}
+
+	out, err := rowScanner[T]()(rows)
+	if err != nil {
+		return empty, 1, fmt.Errorf("error scanning: %w", err)
+	}
+
+	if rows.Next() {
+		return empty, 2, fmt.Errorf("%w: expected 1, got more than 1", ErrUnexpectedRowCountError)
+	}
+	if err := rows.Err(); err != nil {
+		return empty, 1, err
+	}
+
+	return out, 1, nil
 }
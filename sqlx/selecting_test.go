@@ -0,0 +1,79 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetNamedCtx(t *testing.T) {
+	t.Run("exactly one", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectQuery("SELECT id, name FROM users WHERE id = $1").
+			WithArgs(1).
+			WillReturnRows([]string{"id", "name"}, []any{1, "alice"})
+
+		got, err := GetNamedCtx[testUser](context.Background(), db, "SELECT id, name FROM users WHERE id = :id", map[string]any{"id": 1})
+		require.NoError(t, err)
+		require.Equal(t, testUser{ID: 1, Name: "alice"}, got)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectQuery("SELECT id, name FROM users WHERE id = $1").WillReturnRows([]string{"id", "name"})
+
+		_, err := GetNamedCtx[testUser](context.Background(), db, "SELECT id, name FROM users WHERE id = :id", map[string]any{"id": 1})
+		require.ErrorIs(t, err, ErrNotFoundError)
+		require.ErrorIs(t, err, sql.ErrNoRows)
+	})
+
+	t.Run("too many", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		expectation := expectUsers(fake, "SELECT id, name FROM users")
+
+		_, err := GetNamedCtx[testUser](context.Background(), db, "SELECT id, name FROM users", map[string]any{})
+		require.ErrorIs(t, err, ErrUnexpectedRowCountError)
+		require.Equal(t, 2, expectation.RowsRead())
+	})
+}
+
+func TestGetCtx(t *testing.T) {
+	db, fake := newFakeDB(t, "postgres")
+	fake.ExpectQuery("SELECT count(*) FROM users WHERE name = $1").
+		WithArgs("alice").
+		WillReturnRows([]string{"count"}, []any{3})
+
+	got, err := GetCtx[int64](context.Background(), db, "SELECT count(*) FROM users WHERE name = $1", "alice")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), got)
+}
+
+func TestSelectCtx(t *testing.T) {
+	t.Run("rebinds positional arguments", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		expectUsers(fake, "SELECT id, name FROM users WHERE id > $1").WithArgs(1)
+
+		got, err := SelectCtx[testUser](context.Background(), db, "SELECT id, name FROM users WHERE id > ?", 1)
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+	})
+
+	t.Run("no arguments", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectQuery("SELECT id, name FROM users WHERE tags ? 'admin'").WillReturnRows([]string{"id", "name"})
+
+		got, err := SelectCtx[testUser](context.Background(), db, "SELECT id, name FROM users WHERE tags ? 'admin'")
+		require.NoError(t, err)
+		require.Empty(t, got)
+	})
+}
+
+func TestRequireExactSelectCtx(t *testing.T) {
+	db, fake := newFakeDB(t, "mysql")
+	expectUsers(fake, "SELECT id, name FROM users WHERE id > ?")
+
+	_, err := RequireExactSelectCtx[testUser](context.Background(), 1, db, "SELECT id, name FROM users WHERE id > ?", 1)
+	require.ErrorIs(t, err, ErrUnexpectedRowCountError)
+}
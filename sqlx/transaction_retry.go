@@ -0,0 +1,110 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/jmoiron/sqlx"
+	"github.com/sethvargo/go-retry"
+)
+
+const (
+	// SQLStateSerializationFailure is the Postgres SQLSTATE for a serialization failure
+	SQLStateSerializationFailure = "40001"
+	// SQLStateDeadlockDetected is the Postgres SQLSTATE for a detected deadlock
+	SQLStateDeadlockDetected = "40P01"
+
+	defaultTxRetryMaxAttempts = 3
+	defaultTxRetryBaseDelay   = 50 * time.Millisecond
+)
+
+// TxRetryOpts are options used to configure the behavior of WithRetryingTransactionReturning
+type TxRetryOpts struct {
+	// TxOptions are the options each transaction attempt is opened with, nil uses the driver defaults
+	TxOptions *sql.TxOptions
+	// IsRetryable decides if an error returned from a transaction attempt warrants another attempt, nil defaults to
+	// IsSerializationFailure
+	IsRetryable func(error) bool
+	// MaxAttempts is the total number of attempts to make, including the first, 0 defaults to 3
+	MaxAttempts uint64
+	// BaseDelay is the base of the fibonacci backoff between attempts, 0 defaults to 50ms
+	BaseDelay time.Duration
+	// Logger is the logger to which to write retried errors, nil will result in no retries being logged
+	Logger *logr.Logger
+}
+
+type sqlStater interface {
+	SQLState() string
+}
+
+// IsSerializationFailure reports if the given error carries a Postgres serialization failure or deadlock SQLSTATE. Any
+// error in the chain exposing a `SQLState() string` method (such as those from lib/pq or pgx) is inspected
+func IsSerializationFailure(err error) bool {
+	var stater sqlStater
+	if !errors.As(err, &stater) {
+		return false
+	}
+
+	switch stater.SQLState() {
+	case SQLStateSerializationFailure, SQLStateDeadlockDetected:
+		return true
+	default:
+		return false
+	}
+}
+
+// WithRetryingTransaction is like WithRetryingTransactionReturning except does not return a value
+func WithRetryingTransaction(ctx context.Context, db *sqlx.DB, opts TxRetryOpts, workFunc func(*sqlx.Tx) error) error {
+	_, err := WithRetryingTransactionReturning(ctx, db, opts, func(tx *sqlx.Tx) (bool, error) {
+		return false, workFunc(tx)
+	})
+	return err
+}
+
+// WithRetryingTransactionReturning is like WithTransactionReturningCtx, but will retry the entire transaction with a
+// fibonacci backoff when it fails with an error deemed retryable. See TxRetryOpts for configuration options. The work
+// function may be called multiple times, and so should not have side effects outside of the transaction
+func WithRetryingTransactionReturning[T any](ctx context.Context, db *sqlx.DB, opts TxRetryOpts, workFunc func(tx *sqlx.Tx) (T, error)) (T, error) {
+	isRetryable := opts.IsRetryable
+	if isRetryable == nil {
+		isRetryable = IsSerializationFailure
+	}
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultTxRetryMaxAttempts
+	}
+	baseDelay := opts.BaseDelay
+	if baseDelay == 0 {
+		baseDelay = defaultTxRetryBaseDelay
+	}
+
+	attempt := 0
+	var lastErr error
+	backoff := retry.WithMaxRetries(maxAttempts-1, retry.NewFibonacci(baseDelay))
+	out, err := retry.DoValue(ctx, backoff, func(ctx context.Context) (T, error) {
+		attempt += 1
+		out, err := WithTransactionReturningCtx(ctx, db, opts.TxOptions, workFunc)
+		if err != nil && isRetryable(err) {
+			lastErr = err
+			if opts.Logger != nil && uint64(attempt) < maxAttempts {
+				opts.Logger.Info("retrying transaction", "attempt", attempt, "error", err.Error())
+			}
+			return out, retry.RetryableError(err)
+		}
+		return out, err
+	})
+	if err != nil {
+		// Cancellation during a backoff is reported alone, so keep the failure that caused the retry
+		if lastErr != nil && !errors.Is(err, lastErr) {
+			err = fmt.Errorf("%w, last error: %w", err, lastErr)
+		}
+		var empty T
+		return empty, fmt.Errorf("transaction failed after %v attempt(s): %w", attempt, err)
+	}
+
+	return out, nil
+}
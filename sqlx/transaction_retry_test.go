@@ -0,0 +1,121 @@
+package sqlx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSQLStateError mimics the error types of postgres drivers, which expose the SQLSTATE of the failure
+type fakeSQLStateError struct {
+	code string
+}
+
+func (e *fakeSQLStateError) Error() string {
+	return "sqlstate " + e.code
+}
+
+func (e *fakeSQLStateError) SQLState() string {
+	return e.code
+}
+
+func TestIsSerializationFailure(t *testing.T) {
+	require.True(t, IsSerializationFailure(&fakeSQLStateError{code: SQLStateSerializationFailure}))
+	require.True(t, IsSerializationFailure(&fakeSQLStateError{code: SQLStateDeadlockDetected}))
+	require.False(t, IsSerializationFailure(&fakeSQLStateError{code: "23505"}))
+	require.False(t, IsSerializationFailure(errWork))
+}
+
+func TestWithRetryingTransactionReturning(t *testing.T) {
+	opts := TxRetryOpts{BaseDelay: time.Millisecond}
+
+	t.Run("retries serialization failures", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin()
+		fake.ExpectCommit().WillReturnError(&fakeSQLStateError{code: SQLStateSerializationFailure})
+		fake.ExpectBegin()
+		fake.ExpectCommit().WillReturnError(&fakeSQLStateError{code: SQLStateDeadlockDetected})
+		fake.ExpectBegin()
+		fake.ExpectCommit()
+
+		calls := 0
+		got, err := WithRetryingTransactionReturning(context.Background(), db, opts, func(tx *sqlx.Tx) (int, error) {
+			calls += 1
+			return calls, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 3, got)
+	})
+
+	t.Run("stops after max attempts", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		failure := &fakeSQLStateError{code: SQLStateSerializationFailure}
+		fake.ExpectBegin()
+		fake.ExpectCommit().WillReturnError(failure)
+		fake.ExpectBegin()
+		fake.ExpectCommit().WillReturnError(failure)
+
+		opts := opts
+		opts.MaxAttempts = 2
+		calls := 0
+		err := WithRetryingTransaction(context.Background(), db, opts, func(tx *sqlx.Tx) error {
+			calls += 1
+			return nil
+		})
+		require.ErrorIs(t, err, failure)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin()
+		fake.ExpectRollback()
+
+		calls := 0
+		err := WithRetryingTransaction(context.Background(), db, opts, func(tx *sqlx.Tx) error {
+			calls += 1
+			return errWork
+		})
+		require.ErrorIs(t, err, errWork)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("custom classifier", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin().WillReturnError(errWork)
+		fake.ExpectBegin()
+		fake.ExpectCommit()
+
+		opts := opts
+		opts.IsRetryable = func(err error) bool { return errors.Is(err, errWork) }
+		err := WithRetryingTransaction(context.Background(), db, opts, func(tx *sqlx.Tx) error {
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("keeps last error when cancelled during backoff", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin()
+		fake.ExpectRollback()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := opts
+		opts.BaseDelay = time.Minute
+		opts.IsRetryable = func(err error) bool {
+			cancel()
+			return errors.Is(err, errWork)
+		}
+		err := WithRetryingTransaction(ctx, db, opts, func(tx *sqlx.Tx) error {
+			return errWork
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		require.ErrorIs(t, err, errWork)
+	})
+}
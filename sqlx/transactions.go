@@ -1,6 +1,9 @@
 package sqlx
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
@@ -8,25 +11,48 @@ import (
 
 // WithTransaction is like WithTransactionReturning except does not return a value
 func WithTransaction(db *sqlx.DB, workFunc func(*sqlx.Tx) error) error {
-	_, err := WithTransactionReturning(db, func(tx *sqlx.Tx) (bool, error) {
-		return false, workFunc(tx)
-	})
-	return err
+	return WithTransactionCtx(context.Background(), db, nil, workFunc)
 }
 
 // WithTransactionReturning executes the provided function against the provided DB, rolling back if the given function
 // returns error, or committing otherwise. The return value from the work function will be returned on success
 func WithTransactionReturning[T any](db *sqlx.DB, workFunc func(tx *sqlx.Tx) (T, error)) (T, error) {
+	return WithTransactionReturningCtx(context.Background(), db, nil, workFunc)
+}
+
+// WithTransactionCtx is like WithTransactionReturningCtx except does not return a value
+func WithTransactionCtx(ctx context.Context, db *sqlx.DB, opts *sql.TxOptions, workFunc func(*sqlx.Tx) error) error {
+	_, err := WithTransactionReturningCtx(ctx, db, opts, func(tx *sqlx.Tx) (bool, error) {
+		return false, workFunc(tx)
+	})
+	return err
+}
+
+// WithTransactionReturningCtx is like WithTransactionReturning, but the transaction is bound to the given context and
+// opened with the given options (nil uses the driver defaults). If the context is done by the time the work function
+// returns, the transaction is rolled back and the context error is returned. A panic in the work function rolls back
+// the transaction before being re-raised
+func WithTransactionReturningCtx[T any](ctx context.Context, db *sqlx.DB, opts *sql.TxOptions, workFunc func(tx *sqlx.Tx) (T, error)) (T, error) {
 	var empty T
 
-	txn, err := db.Beginx()
+	txn, err := db.BeginTxx(ctx, opts)
 	if err != nil {
 		return empty, fmt.Errorf("error opening transaction: %w", err)
 	}
 
+	defer func() {
+		if p := recover(); p != nil {
+			_ = txn.Rollback()
+			panic(p)
+		}
+	}()
+
 	out, workErr := workFunc(txn)
+	if workErr == nil {
+		workErr = ctx.Err()
+	}
 	if workErr != nil {
-		if err := txn.Rollback(); err != nil {
+		if err := rollback(txn); err != nil {
 			return empty, fmt.Errorf("error rolling back: %w, rollback caused by: %w", err, workErr)
 		}
 		return empty, workErr
@@ -38,3 +64,12 @@ func WithTransactionReturning[T any](db *sqlx.DB, workFunc func(tx *sqlx.Tx) (T,
 
 	return out, nil
 }
+
+// rollback rolls back the given transaction, ignoring the case where database/sql has already rolled it back due to
+// context cancellation
+func rollback(txn *sqlx.Tx) error {
+	if err := txn.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return err
+	}
+	return nil
+}
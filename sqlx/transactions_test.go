@@ -0,0 +1,81 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+var errWork = errors.New("work error")
+
+func TestWithTransactionReturningCtx(t *testing.T) {
+	t.Run("commits on success", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin()
+		fake.ExpectCommit()
+
+		got, err := WithTransactionReturningCtx(context.Background(), db, nil, func(tx *sqlx.Tx) (int, error) {
+			return 7, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 7, got)
+	})
+
+	t.Run("rolls back on error", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin()
+		fake.ExpectRollback()
+
+		got, err := WithTransactionReturningCtx(context.Background(), db, nil, func(tx *sqlx.Tx) (int, error) {
+			return 7, errWork
+		})
+		require.ErrorIs(t, err, errWork)
+		require.Equal(t, 0, got)
+	})
+
+	t.Run("passes options", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin().WithTxOptions(sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true})
+		fake.ExpectCommit()
+
+		err := WithTransactionCtx(
+			context.Background(),
+			db,
+			&sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true},
+			func(tx *sqlx.Tx) error { return nil },
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("rolls back when context is done", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin()
+		fake.ExpectRollback()
+		ctx, cancel := context.WithCancel(context.Background())
+
+		err := WithTransactionCtx(ctx, db, nil, func(tx *sqlx.Tx) error {
+			cancel()
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		// database/sql may perform the rollback asynchronously on cancellation
+		require.Eventually(t, fake.ExpectationsMet, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("rolls back and re-panics", func(t *testing.T) {
+		db, fake := newFakeDB(t, "postgres")
+		fake.ExpectBegin()
+		fake.ExpectRollback()
+
+		require.PanicsWithValue(t, "boom", func() {
+			_ = WithTransaction(db, func(tx *sqlx.Tx) error {
+				panic("boom")
+			})
+		})
+	})
+}
@@ -0,0 +1,476 @@
+package testhlp
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// FakeDriverName is the name the fake driver is registered with in database/sql
+	FakeDriverName = "hlp_fake"
+)
+
+var (
+	ErrUnexpectedCallError = errors.New("unexpected call to fake database")
+
+	registerOnce sync.Once
+	registryMu   sync.Mutex
+	registry     = map[string]*FakeDB{}
+	registryID   = 0
+)
+
+type anyArg struct{}
+
+// AnyArg matches any argument value when passed to Expectation.WithArgs
+var AnyArg = anyArg{}
+
+type expectationKind string
+
+const (
+	kindBegin    expectationKind = "begin"
+	kindCommit   expectationKind = "commit"
+	kindRollback expectationKind = "rollback"
+	kindQuery    expectationKind = "query"
+	kindExec     expectationKind = "exec"
+)
+
+// Expectation is a single expected call against a FakeDB, configured through its With/Will methods
+type Expectation struct {
+	kind    expectationKind
+	query   string
+	pattern *regexp.Regexp
+	args    []any
+	hasArgs bool
+	txOpts  *sql.TxOptions
+
+	columns      []string
+	rows         [][]driver.Value
+	rowErr       error
+	lastInsertID int64
+	rowsAffected int64
+	err          error
+	delay        time.Duration
+
+	met        bool
+	rowsRead   atomic.Int64
+	rowsClosed atomic.Bool
+}
+
+// WithArgs requires the call to be made with exactly the given arguments. Arguments are compared after conversion to
+// driver values, and AnyArg matches any value
+func (e *Expectation) WithArgs(args ...any) *Expectation {
+	e.args = args
+	e.hasArgs = true
+	return e
+}
+
+// WithTxOptions requires an expected begin to be made with the given isolation level and read only flag
+func (e *Expectation) WithTxOptions(opts sql.TxOptions) *Expectation {
+	e.txOpts = &opts
+	return e
+}
+
+// WillReturnRows sets the columns and rows returned by an expected query
+func (e *Expectation) WillReturnRows(columns []string, rows ...[]any) *Expectation {
+	e.columns = columns
+	e.rows = make([][]driver.Value, len(rows))
+	for i, row := range rows {
+		e.rows[i] = make([]driver.Value, len(row))
+		for j, val := range row {
+			converted, err := driver.DefaultParameterConverter.ConvertValue(val)
+			if err != nil {
+				panic(fmt.Sprintf("unable to convert row %v column %v: %v", i, j, err))
+			}
+			e.rows[i][j] = converted
+		}
+	}
+	return e
+}
+
+// WillReturnRowError sets the error returned when iterating past the last row of an expected query, as if the
+// connection failed part way through reading the results
+func (e *Expectation) WillReturnRowError(err error) *Expectation {
+	e.rowErr = err
+	return e
+}
+
+// WillReturnResult sets the result of an expected exec
+func (e *Expectation) WillReturnResult(lastInsertID int64, rowsAffected int64) *Expectation {
+	e.lastInsertID = lastInsertID
+	e.rowsAffected = rowsAffected
+	return e
+}
+
+// WillReturnError makes the expected call fail with the given error
+func (e *Expectation) WillReturnError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// WillDelayFor makes the expected call take at least the given duration, or until its context is done
+func (e *Expectation) WillDelayFor(d time.Duration) *Expectation {
+	e.delay = d
+	return e
+}
+
+// RowsRead returns the number of rows the caller has read from an expected query
+func (e *Expectation) RowsRead() int {
+	return int(e.rowsRead.Load())
+}
+
+// RowsClosed returns true once the caller has closed the rows of an expected query
+func (e *Expectation) RowsClosed() bool {
+	return e.rowsClosed.Load()
+}
+
+func (e *Expectation) wait(ctx context.Context) error {
+	if e.delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(e.delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *Expectation) String() string {
+	switch {
+	case e.pattern != nil:
+		return fmt.Sprintf("%v matching %q", e.kind, e.pattern.String())
+	case e.kind == kindQuery || e.kind == kindExec:
+		return fmt.Sprintf("%v %q", e.kind, e.query)
+	default:
+		return string(e.kind)
+	}
+}
+
+func (e *Expectation) matches(kind expectationKind, query string, args []driver.NamedValue, txOpts driver.TxOptions) error {
+	if e.kind != kind {
+		return fmt.Errorf("expected %v, got %v %q", e, kind, query)
+	}
+
+	if e.txOpts != nil {
+		if sql.IsolationLevel(txOpts.Isolation) != e.txOpts.Isolation || txOpts.ReadOnly != e.txOpts.ReadOnly {
+			return fmt.Errorf("%v expected options %+v, got %+v", e, *e.txOpts, txOpts)
+		}
+	}
+
+	if e.pattern != nil && !e.pattern.MatchString(query) {
+		return fmt.Errorf("expected %v, got %q", e, query)
+	}
+	if e.pattern == nil && (kind == kindQuery || kind == kindExec) && e.query != strings.TrimSpace(query) {
+		return fmt.Errorf("expected %v, got %q", e, query)
+	}
+
+	if !e.hasArgs {
+		return nil
+	}
+	if len(e.args) != len(args) {
+		return fmt.Errorf("%v expected %v args, got %v", e, len(e.args), len(args))
+	}
+	for i, want := range e.args {
+		if want == AnyArg {
+			continue
+		}
+		converted, err := driver.DefaultParameterConverter.ConvertValue(want)
+		if err != nil {
+			return fmt.Errorf("%v unable to convert expected arg %v: %w", e, i, err)
+		}
+		if !reflect.DeepEqual(converted, args[i].Value) {
+			return fmt.Errorf("%v arg %v expected %#v, got %#v", e, i, converted, args[i].Value)
+		}
+	}
+
+	return nil
+}
+
+// FakeDB is an in-process fake database, registered with database/sql as FakeDriverName. Calls made through it must
+// match the expectations registered against it, in order. Unexpected calls fail with ErrUnexpectedCallError and are
+// reported to the TestingT
+type FakeDB struct {
+	t   TestingT
+	dsn string
+
+	mu           sync.Mutex
+	expectations []*Expectation
+	calls        []string
+}
+
+// NewFakeDB creates a FakeDB reporting to the given TestingT, and opens a *sql.DB backed by it. Wrap the result with
+// sqlx.NewDb to choose a bindvar style. If the TestingT supports Cleanup (such as *testing.T), AssertExpectations is
+// called automatically at the end of the test
+func NewFakeDB(t TestingT) (*sql.DB, *FakeDB) {
+	registerOnce.Do(func() {
+		sql.Register(FakeDriverName, fakeDriver{})
+	})
+
+	registryMu.Lock()
+	registryID += 1
+	fake := &FakeDB{
+		t:   t,
+		dsn: fmt.Sprintf("fake-%v", registryID),
+	}
+	registry[fake.dsn] = fake
+	registryMu.Unlock()
+
+	db, err := sql.Open(FakeDriverName, fake.dsn)
+	if err != nil {
+		// sql.Open only errors for unknown drivers, which cannot happen here
+		panic(err)
+	}
+
+	if cleaner, ok := t.(interface{ Cleanup(func()) }); ok {
+		cleaner.Cleanup(func() {
+			fake.AssertExpectations()
+			_ = db.Close()
+			registryMu.Lock()
+			delete(registry, fake.dsn)
+			registryMu.Unlock()
+		})
+	}
+
+	return db, fake
+}
+
+func (f *FakeDB) expect(e *Expectation) *Expectation {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.expectations = append(f.expectations, e)
+	return e
+}
+
+// ExpectBegin expects a transaction to be opened
+func (f *FakeDB) ExpectBegin() *Expectation {
+	return f.expect(&Expectation{kind: kindBegin})
+}
+
+// ExpectCommit expects a transaction to be committed
+func (f *FakeDB) ExpectCommit() *Expectation {
+	return f.expect(&Expectation{kind: kindCommit})
+}
+
+// ExpectRollback expects a transaction to be rolled back
+func (f *FakeDB) ExpectRollback() *Expectation {
+	return f.expect(&Expectation{kind: kindRollback})
+}
+
+// ExpectQuery expects a query exactly matching the given text, ignoring leading and trailing whitespace
+func (f *FakeDB) ExpectQuery(query string) *Expectation {
+	return f.expect(&Expectation{kind: kindQuery, query: strings.TrimSpace(query)})
+}
+
+// ExpectQueryRegex expects a query matching the given regular expression
+func (f *FakeDB) ExpectQueryRegex(pattern string) *Expectation {
+	return f.expect(&Expectation{kind: kindQuery, pattern: regexp.MustCompile(pattern)})
+}
+
+// ExpectExec expects an exec exactly matching the given text, ignoring leading and trailing whitespace
+func (f *FakeDB) ExpectExec(query string) *Expectation {
+	return f.expect(&Expectation{kind: kindExec, query: strings.TrimSpace(query)})
+}
+
+// ExpectExecRegex expects an exec matching the given regular expression
+func (f *FakeDB) ExpectExecRegex(pattern string) *Expectation {
+	return f.expect(&Expectation{kind: kindExec, pattern: regexp.MustCompile(pattern)})
+}
+
+// ExpectationsMet returns true if every expectation has been met. Unlike AssertExpectations it reports nothing, and so
+// can be polled for calls that database/sql makes asynchronously, such as the rollback of a cancelled transaction
+func (f *FakeDB) ExpectationsMet() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, e := range f.expectations {
+		if !e.met {
+			return false
+		}
+	}
+	return true
+}
+
+// Calls returns a description of every call made against the fake, expected or not, in order. Begin, commit and
+// rollback are described by name, and queries and execs as `query: <query>` and `exec: <query>`
+func (f *FakeDB) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.calls...)
+}
+
+// AssertExpectations reports every expectation that was not met to the TestingT
+func (f *FakeDB) AssertExpectations() {
+	f.t.Helper()
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, e := range f.expectations {
+		if !e.met {
+			f.t.Errorf("expected %v was not called", e)
+		}
+	}
+}
+
+// next consumes the next unmet expectation, which must match the given call
+func (f *FakeDB) next(kind expectationKind, query string, args []driver.NamedValue, txOpts driver.TxOptions) (*Expectation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if query == "" {
+		f.calls = append(f.calls, string(kind))
+	} else {
+		f.calls = append(f.calls, fmt.Sprintf("%v: %v", kind, query))
+	}
+
+	for _, e := range f.expectations {
+		if e.met {
+			continue
+		}
+		if err := e.matches(kind, query, args, txOpts); err != nil {
+			f.t.Errorf("%v: %v", ErrUnexpectedCallError, err)
+			return nil, fmt.Errorf("%w: %w", ErrUnexpectedCallError, err)
+		}
+		e.met = true
+		return e, e.err
+	}
+
+	err := fmt.Errorf("%w: no expectations remaining for %v %q", ErrUnexpectedCallError, kind, query)
+	f.t.Errorf("%v", err)
+	return nil, err
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	fake, ok := registry[dsn]
+	if !ok {
+		return nil, fmt.Errorf("no fake database registered for %q", dsn)
+	}
+	return &fakeConn{db: fake}, nil
+}
+
+type fakeConn struct {
+	db *FakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepared statements are not supported by the fake driver: %q", query)
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	e, err := c.db.next(kindBegin, "", nil, opts)
+	if e != nil {
+		if waitErr := e.wait(ctx); waitErr != nil {
+			return nil, waitErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &fakeTx{db: c.db}, nil
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, err := c.db.next(kindExec, query, args, driver.TxOptions{})
+	if e != nil {
+		if waitErr := e.wait(ctx); waitErr != nil {
+			return nil, waitErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fakeResult{lastInsertID: e.lastInsertID, rowsAffected: e.rowsAffected}, nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	e, err := c.db.next(kindQuery, query, args, driver.TxOptions{})
+	if e != nil {
+		if waitErr := e.wait(ctx); waitErr != nil {
+			return nil, waitErr
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &fakeRows{expectation: e}, nil
+}
+
+type fakeTx struct {
+	db *FakeDB
+}
+
+func (t *fakeTx) Commit() error {
+	_, err := t.db.next(kindCommit, "", nil, driver.TxOptions{})
+	return err
+}
+
+func (t *fakeTx) Rollback() error {
+	_, err := t.db.next(kindRollback, "", nil, driver.TxOptions{})
+	return err
+}
+
+type fakeResult struct {
+	lastInsertID int64
+	rowsAffected int64
+}
+
+func (r fakeResult) LastInsertId() (int64, error) {
+	return r.lastInsertID, nil
+}
+
+func (r fakeResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
+type fakeRows struct {
+	expectation *Expectation
+	idx         int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.expectation.columns
+}
+
+func (r *fakeRows) Close() error {
+	r.expectation.rowsClosed.Store(true)
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.idx >= len(r.expectation.rows) {
+		if r.expectation.rowErr != nil {
+			return r.expectation.rowErr
+		}
+		return io.EOF
+	}
+	copy(dest, r.expectation.rows[r.idx])
+	r.idx += 1
+	r.expectation.rowsRead.Store(int64(r.idx))
+	return nil
+}
@@ -0,0 +1,125 @@
+package testhlp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	hlpsqlx "github.com/nicjohnson145/hlp/sqlx"
+	mock "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeUser struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func TestFakeDB(t *testing.T) {
+	t.Run("scripted transaction", func(t *testing.T) {
+		db, fake := NewFakeDB(t)
+		fake.ExpectBegin()
+		fake.ExpectQueryRegex(`^SELECT id, name FROM users WHERE name = \$1$`).
+			WithArgs("alice").
+			WillReturnRows([]string{"id", "name"}, []any{1, "alice"})
+		fake.ExpectExec("UPDATE users SET name = $1 WHERE id = $2").
+			WithArgs("bob", AnyArg).
+			WillReturnResult(0, 1)
+		fake.ExpectCommit()
+
+		err := hlpsqlx.WithTransaction(sqlx.NewDb(db, "postgres"), func(tx *sqlx.Tx) error {
+			user, err := hlpsqlx.GetNamedCtx[fakeUser](context.Background(), tx, "SELECT id, name FROM users WHERE name = :name", map[string]any{"name": "alice"})
+			if err != nil {
+				return err
+			}
+			require.Equal(t, fakeUser{ID: 1, Name: "alice"}, user)
+
+			_, err = hlpsqlx.RequireExactExecCtx(context.Background(), 1, tx, "UPDATE users SET name = ? WHERE id = ?", "bob", user.ID)
+			return err
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("scripted errors roll back", func(t *testing.T) {
+		boom := errors.New("boom")
+
+		db, fake := NewFakeDB(t)
+		fake.ExpectBegin()
+		fake.ExpectExec("DELETE FROM users").WillReturnError(boom)
+		fake.ExpectRollback()
+
+		err := hlpsqlx.WithTransaction(sqlx.NewDb(db, "postgres"), func(tx *sqlx.Tx) error {
+			_, err := tx.Exec("DELETE FROM users")
+			return err
+		})
+		require.ErrorIs(t, err, boom)
+	})
+
+	t.Run("reports unmet and unexpected calls", func(t *testing.T) {
+		testT := &MockTestingT{}
+		testT.EXPECT().Helper().Return()
+		testT.EXPECT().Errorf(mock.Anything, mock.Anything).Return()
+		testT.EXPECT().Errorf(mock.Anything, mock.Anything, mock.Anything).Return()
+
+		db, fake := NewFakeDB(testT)
+		fake.ExpectExec("DELETE FROM users").WithArgs(1)
+		fake.ExpectCommit()
+
+		_, err := db.Exec("DELETE FROM users", 2)
+		require.ErrorIs(t, err, ErrUnexpectedCallError)
+		_, err = db.Exec("DELETE FROM users", 1)
+		require.NoError(t, err)
+
+		fake.AssertExpectations()
+
+		messages := []string{}
+		for _, call := range testT.Mock.Calls {
+			if call.Method != "Errorf" {
+				continue
+			}
+			format := call.Arguments.String(0)
+			messages = append(messages, format)
+		}
+		require.Equal(t, []string{"%v: %v", "expected %v was not called"}, messages)
+	})
+
+	t.Run("options, delays and row state", func(t *testing.T) {
+		boom := errors.New("boom")
+
+		db, fake := NewFakeDB(t)
+		fake.ExpectBegin().WithTxOptions(sql.TxOptions{Isolation: sql.LevelSerializable})
+		fake.ExpectQuery("SELECT id FROM users").
+			WillDelayFor(5*time.Millisecond).
+			WillReturnRows([]string{"id"}, []any{1}, []any{2}).
+			WillReturnRowError(boom)
+		rows := fake.ExpectQuery("SELECT id FROM users").WillReturnRows([]string{"id"}, []any{1}, []any{2})
+		fake.ExpectRollback()
+		require.False(t, fake.ExpectationsMet())
+
+		tx, err := db.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable})
+		require.NoError(t, err)
+
+		start := time.Now()
+		failing, err := tx.Query("SELECT id FROM users")
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, time.Since(start), 5*time.Millisecond)
+		for failing.Next() {
+		}
+		require.ErrorIs(t, failing.Err(), boom)
+
+		partial, err := tx.Query("SELECT id FROM users")
+		require.NoError(t, err)
+		require.True(t, partial.Next())
+		require.Equal(t, 1, rows.RowsRead())
+		require.False(t, rows.RowsClosed())
+		require.NoError(t, partial.Close())
+		require.True(t, rows.RowsClosed())
+
+		require.NoError(t, tx.Rollback())
+		require.True(t, fake.ExpectationsMet())
+		require.Equal(t, []string{"begin", "query: SELECT id FROM users", "query: SELECT id FROM users", "rollback"}, fake.Calls())
+	})
+}
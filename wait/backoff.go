@@ -0,0 +1,59 @@
+package wait
+
+import (
+	"time"
+
+	"github.com/sethvargo/go-retry"
+)
+
+// BackoffStrategy is the algorithm used to space out retries
+type BackoffStrategy int
+
+const (
+	// BackoffFibonacci waits base, base, 2*base, 3*base, 5*base... between attempts
+	BackoffFibonacci BackoffStrategy = iota
+	// BackoffConstant waits base between every attempt
+	BackoffConstant
+	// BackoffExponential waits base, 2*base, 4*base, 8*base... between attempts
+	BackoffExponential
+)
+
+// BackoffOpts are options used to configure how long to wait between retries
+type BackoffOpts struct {
+	// Strategy is the backoff algorithm to use, defaulting to BackoffFibonacci
+	Strategy BackoffStrategy
+	// Base is the initial interval of the strategy, 0 defaults to 1s
+	Base time.Duration
+	// Jitter is the maximum random amount of time added to or subtracted from each interval, 0 disables jitter
+	Jitter time.Duration
+	// MaxInterval caps the interval between any two attempts, 0 leaves intervals uncapped
+	MaxInterval time.Duration
+}
+
+// Backoff creates a new retry.Backoff as configured. Backoffs are stateful, so a new one should be created for each
+// sequence of retries
+func (b BackoffOpts) Backoff() retry.Backoff {
+	base := b.Base
+	if base == 0 {
+		base = 1 * time.Second
+	}
+
+	var backoff retry.Backoff
+	switch b.Strategy {
+	case BackoffConstant:
+		backoff = retry.NewConstant(base)
+	case BackoffExponential:
+		backoff = retry.NewExponential(base)
+	default:
+		backoff = retry.NewFibonacci(base)
+	}
+
+	if b.Jitter > 0 {
+		backoff = retry.WithJitter(b.Jitter, backoff)
+	}
+	if b.MaxInterval > 0 {
+		backoff = retry.WithCappedDuration(b.MaxInterval, backoff)
+	}
+
+	return backoff
+}
@@ -0,0 +1,37 @@
+package wait
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffOpts(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		backoff := BackoffOpts{}.Backoff()
+		for _, want := range []time.Duration{1 * time.Second, 2 * time.Second, 3 * time.Second} {
+			got, stop := backoff.Next()
+			require.False(t, stop)
+			require.Equal(t, want, got)
+		}
+	})
+
+	t.Run("capped exponential", func(t *testing.T) {
+		backoff := BackoffOpts{Strategy: BackoffExponential, Base: time.Second, MaxInterval: 3 * time.Second}.Backoff()
+		for _, want := range []time.Duration{1 * time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+			got, stop := backoff.Next()
+			require.False(t, stop)
+			require.Equal(t, want, got)
+		}
+	})
+
+	t.Run("constant with jitter", func(t *testing.T) {
+		backoff := BackoffOpts{Strategy: BackoffConstant, Base: time.Second, Jitter: 100 * time.Millisecond}.Backoff()
+		for i := 0; i < 10; i++ {
+			got, stop := backoff.Next()
+			require.False(t, stop)
+			require.InDelta(t, float64(time.Second), float64(got), float64(100*time.Millisecond))
+		}
+	})
+}
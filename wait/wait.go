@@ -0,0 +1,180 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/sethvargo/go-retry"
+)
+
+var (
+	ErrUnexpectedStatusError = errors.New("unexpected status code")
+)
+
+// Probe checks whether a dependency is ready, returning nil once it is
+type Probe func(ctx context.Context) error
+
+// ContextPinger is satisfied by *sql.DB, and any other client exposing a context aware ping
+type ContextPinger interface {
+	PingContext(context.Context) error
+}
+
+// Dependency is a named Probe to wait for
+type Dependency struct {
+	// Name identifies the dependency in logs and errors
+	Name string
+	// Probe is called until it returns nil
+	Probe Probe
+	// Timeout is the amount of time to wait for this dependency, overriding Opts.Timeout when non-zero
+	Timeout time.Duration
+}
+
+// Opts are options used to configure the behavior of For
+type Opts struct {
+	// Timeout is the amount of time to wait for each dependency before erroring, 0 represents an indefinite wait
+	Timeout time.Duration
+	// Backoff configures the interval between probes of a single dependency
+	Backoff BackoffOpts
+	// MaxAttempts is the maximum number of probes of each dependency before erroring, 0 represents unlimited attempts
+	MaxAttempts uint64
+	// OnAttempt, if set, is called after every probe with the name of the dependency, the 1-based attempt number and
+	// the resulting error, which is nil for the successful attempt. Dependencies are probed concurrently, so it may be
+	// called from multiple goroutines at once
+	OnAttempt func(name string, attempt int, err error)
+	// Logger is the logger to which to write probe failures, nil will result in no failures being logged. Failed
+	// probes that will be retried are logged at V(1), and only a dependency that never became ready is logged as an
+	// error
+	Logger *logr.Logger
+}
+
+// NotReadyError is returned, joined with any others, for each dependency that did not become ready
+type NotReadyError struct {
+	// Name is the name of the dependency
+	Name string
+	// Err wraps the reason the wait stopped, along with the last probe error if there was one
+	Err error
+}
+
+func (e *NotReadyError) Error() string {
+	return fmt.Sprintf("dependency %v never became ready: %v", e.Name, e.Err)
+}
+
+func (e *NotReadyError) Unwrap() error {
+	return e.Err
+}
+
+// For concurrently waits for all of the given dependencies to become ready. If any do not become ready before their
+// timeout or the cancellation of ctx, the result is an errors.Join of a *NotReadyError for each of them
+func For(ctx context.Context, opts Opts, deps ...Dependency) error {
+	errs := make([]error, len(deps))
+
+	var wg sync.WaitGroup
+	for i, dep := range deps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = waitFor(ctx, opts, dep)
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+func waitFor(ctx context.Context, opts Opts, dep Dependency) error {
+	timeout := opts.Timeout
+	if dep.Timeout != 0 {
+		timeout = dep.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	backoff := opts.Backoff.Backoff()
+	if opts.MaxAttempts > 0 {
+		backoff = retry.WithMaxRetries(opts.MaxAttempts-1, backoff)
+	}
+
+	attempt := 0
+	var lastErr error
+	err := retry.Do(ctx, backoff, func(ctx context.Context) error {
+		attempt += 1
+		err := dep.Probe(ctx)
+		if opts.OnAttempt != nil {
+			opts.OnAttempt(dep.Name, attempt, err)
+		}
+		if err != nil {
+			lastErr = err
+			if opts.Logger != nil {
+				opts.Logger.V(1).Info("dependency not ready, retrying", "dependency", dep.Name, "attempt", attempt, "error", err.Error())
+			}
+			return retry.RetryableError(err)
+		}
+		return nil
+	})
+	if err == nil {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		err = context.Cause(ctx)
+	}
+	if lastErr != nil && !errors.Is(err, lastErr) {
+		err = fmt.Errorf("%w, last error: %w", err, lastErr)
+	}
+	if opts.Logger != nil {
+		opts.Logger.Error(err, "dependency never became ready", "dependency", dep.Name, "attempts", attempt)
+	}
+	return &NotReadyError{Name: dep.Name, Err: err}
+}
+
+// TCP creates a Probe that succeeds once a TCP connection can be established to the given address
+func TCP(addr string) Probe {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// HTTP creates a Probe that succeeds once a GET request to the given url returns a 2xx status code. A nil client uses
+// http.DefaultClient
+func HTTP(client *http.Client, url string) Probe {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("%w: %v", ErrUnexpectedStatusError, resp.StatusCode)
+		}
+		return nil
+	}
+}
+
+// Pinger creates a Probe that succeeds once the given ContextPinger (such as *sql.DB) responds without error
+func Pinger(pinger ContextPinger) Probe {
+	return pinger.PingContext
+}
@@ -0,0 +1,156 @@
+package wait
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/require"
+)
+
+var fastBackoff = BackoffOpts{Strategy: BackoffConstant, Base: time.Millisecond}
+
+type funcPinger func(context.Context) error
+
+func (f funcPinger) PingContext(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestFor(t *testing.T) {
+	t.Run("all ready", func(t *testing.T) {
+		var attempts atomic.Int64
+		flaky := func(context.Context) error {
+			if attempts.Add(1) < 3 {
+				return errors.New("not yet")
+			}
+			return nil
+		}
+
+		err := For(
+			context.Background(),
+			Opts{Backoff: fastBackoff, Timeout: time.Second},
+			Dependency{Name: "flaky", Probe: flaky},
+			Dependency{Name: "pinger", Probe: Pinger(funcPinger(func(context.Context) error { return nil }))},
+		)
+		require.NoError(t, err)
+		require.Equal(t, int64(3), attempts.Load())
+	})
+
+	t.Run("names every failure", func(t *testing.T) {
+		probeErr := errors.New("connection refused")
+		failing := func(context.Context) error { return probeErr }
+
+		err := For(
+			context.Background(),
+			Opts{Backoff: fastBackoff, Timeout: time.Second},
+			Dependency{Name: "redis", Probe: failing, Timeout: 10 * time.Millisecond},
+			Dependency{Name: "ok", Probe: func(context.Context) error { return nil }},
+			Dependency{Name: "postgres", Probe: failing, Timeout: 10 * time.Millisecond},
+		)
+		require.ErrorIs(t, err, probeErr)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "dependency redis never became ready")
+		require.ErrorContains(t, err, "dependency postgres never became ready")
+		require.NotContains(t, err.Error(), "dependency ok")
+
+		var notReady *NotReadyError
+		require.ErrorAs(t, err, &notReady)
+		require.Equal(t, "redis", notReady.Name)
+	})
+
+	t.Run("max attempts and callback", func(t *testing.T) {
+		probeErr := errors.New("connection refused")
+
+		logs := []string{}
+		logger := funcr.New(func(prefix, args string) {
+			logs = append(logs, args)
+		}, funcr.Options{Verbosity: 1})
+
+		attempts := []int{}
+		err := For(
+			context.Background(),
+			Opts{
+				Backoff:     fastBackoff,
+				MaxAttempts: 3,
+				Logger:      &logger,
+				OnAttempt: func(name string, attempt int, err error) {
+					require.Equal(t, "redis", name)
+					require.ErrorIs(t, err, probeErr)
+					attempts = append(attempts, attempt)
+				},
+			},
+			Dependency{Name: "redis", Probe: func(context.Context) error { return probeErr }},
+		)
+		require.ErrorIs(t, err, probeErr)
+		require.Equal(t, []int{1, 2, 3}, attempts)
+
+		require.Len(t, logs, 4)
+		for _, line := range logs[:3] {
+			require.Contains(t, line, `"level"=1`)
+		}
+		require.Contains(t, logs[3], `"msg"="dependency never became ready"`)
+	})
+
+	t.Run("cancelled with cause", func(t *testing.T) {
+		cause := errors.New("sigterm")
+		ctx, cancel := context.WithCancelCause(context.Background())
+
+		err := For(
+			ctx,
+			Opts{
+				Backoff: fastBackoff,
+				OnAttempt: func(_ string, attempt int, _ error) {
+					if attempt == 2 {
+						cancel(cause)
+					}
+				},
+			},
+			Dependency{Name: "redis", Probe: func(context.Context) error { return errors.New("connection refused") }},
+		)
+		require.ErrorIs(t, err, cause)
+		require.ErrorContains(t, err, "connection refused")
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := For(ctx, Opts{}, Dependency{Name: "never", Probe: func(context.Context) error { return nil }})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+
+	require.NoError(t, TCP(addr)(context.Background()))
+
+	require.NoError(t, listener.Close())
+	require.Error(t, TCP(addr)(context.Background()))
+}
+
+func TestHTTP(t *testing.T) {
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	probe := HTTP(nil, server.URL)
+	require.ErrorIs(t, probe(context.Background()), ErrUnexpectedStatusError)
+
+	healthy.Store(true)
+	require.NoError(t, probe(context.Background()))
+}